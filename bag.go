@@ -0,0 +1,281 @@
+package linq
+
+import (
+	"iter"
+)
+
+// Bag 多重集合，记录每个元素出现的次数，元素按首次加入顺序遍历，零值可直接使用
+type Bag[T comparable] struct {
+	counts map[T]int
+	keys   Set[T]
+	total  int
+}
+
+// NewBag 创建多重集合并添加初始元素
+func NewBag[T comparable](items ...T) *Bag[T] {
+	b := &Bag[T]{counts: make(map[T]int, len(items))}
+	b.Add(items...)
+	return b
+}
+
+// ToBag 将查询结果收集为多重集合
+func ToBag[T comparable](q Query[T]) *Bag[T] {
+	b := &Bag[T]{counts: make(map[T]int, q.capacity)}
+	for item := range q.Seq() {
+		b.AddN(item, 1)
+	}
+	return b
+}
+
+// FromBag 从多重集合创建 Query 查询对象，每个元素按其次数重复输出
+func FromBag[T comparable](b *Bag[T]) Query[T] {
+	return Query[T]{
+		iterate:  b.All(),
+		capacity: b.Len(),
+	}
+}
+
+// Add 每个参数计数加一
+func (b *Bag[T]) Add(items ...T) {
+	for _, item := range items {
+		b.AddN(item, 1)
+	}
+}
+
+// AddN 元素计数增加 n，n <= 0 时忽略
+func (b *Bag[T]) AddN(item T, n int) {
+	if n <= 0 {
+		return
+	}
+	if b.counts == nil {
+		b.counts = make(map[T]int)
+	}
+	if b.counts[item] == 0 {
+		b.keys.Add(item)
+	}
+	b.counts[item] += n
+	b.total += n
+}
+
+// Remove 每个参数计数减一，返回实际移除的个数
+func (b *Bag[T]) Remove(items ...T) int {
+	removed := 0
+	for _, item := range items {
+		removed += b.RemoveN(item, 1)
+	}
+	return removed
+}
+
+// RemoveN 元素计数最多减少 n，计数归零时移除该元素，返回实际减少的次数
+func (b *Bag[T]) RemoveN(item T, n int) int {
+	count := b.counts[item]
+	if n <= 0 || count == 0 {
+		return 0
+	}
+	if n >= count {
+		delete(b.counts, item)
+		b.keys.Remove(item)
+		b.total -= count
+		return count
+	}
+	b.counts[item] = count - n
+	b.total -= n
+	return n
+}
+
+// Count 返回元素的次数
+func (b *Bag[T]) Count(item T) int {
+	return b.counts[item]
+}
+
+// Has 判断是否包含元素
+func (b *Bag[T]) Has(item T) bool {
+	return b.counts[item] > 0
+}
+
+// Len 返回所有元素次数之和
+func (b *Bag[T]) Len() int {
+	return b.total
+}
+
+// Unique 返回不同元素的个数
+func (b *Bag[T]) Unique() int {
+	return len(b.counts)
+}
+
+// All 按首次加入顺序遍历，每个元素重复其次数
+func (b *Bag[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for item := range b.keys.All() {
+			for n := b.counts[item]; n > 0; n-- {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Counts 按首次加入顺序遍历元素及其次数
+func (b *Bag[T]) Counts() iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		for item := range b.keys.All() {
+			if !yield(item, b.counts[item]) {
+				return
+			}
+		}
+	}
+}
+
+// Clone 复制多重集合
+func (b *Bag[T]) Clone() *Bag[T] {
+	result := &Bag[T]{counts: make(map[T]int, len(b.counts))}
+	for item, n := range b.Counts() {
+		result.AddN(item, n)
+	}
+	return result
+}
+
+// Sum 返回两者次数相加的多重集合
+func (b *Bag[T]) Sum(other *Bag[T]) *Bag[T] {
+	result := b.Clone()
+	for item, n := range other.Counts() {
+		result.AddN(item, n)
+	}
+	return result
+}
+
+// Union 返回每个元素取最大次数的多重集合
+func (b *Bag[T]) Union(other *Bag[T]) *Bag[T] {
+	result := b.Clone()
+	for item, n := range other.Counts() {
+		result.AddN(item, n-result.Count(item))
+	}
+	return result
+}
+
+// Intersect 返回每个元素取最小次数的多重集合
+func (b *Bag[T]) Intersect(other *Bag[T]) *Bag[T] {
+	result := NewBag[T]()
+	for item, n := range b.Counts() {
+		result.AddN(item, min(n, other.Count(item)))
+	}
+	return result
+}
+
+// Difference 返回次数相减（不小于 0）的多重集合
+func (b *Bag[T]) Difference(other *Bag[T]) *Bag[T] {
+	result := NewBag[T]()
+	for item, n := range b.Counts() {
+		result.AddN(item, n-other.Count(item))
+	}
+	return result
+}
+
+// IsSubset 判断每个元素的次数都不超过 other 中的次数
+func (b *Bag[T]) IsSubset(other *Bag[T]) bool {
+	if b.total > other.total {
+		return false
+	}
+	for item, n := range b.Counts() {
+		if n > other.Count(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal 判断两个多重集合的元素及次数是否完全相同
+func (b *Bag[T]) Equal(other *Bag[T]) bool {
+	return b.total == other.total && len(b.counts) == len(other.counts) && b.IsSubset(other)
+}
+
+// CountBy 按键统计元素个数
+func CountBy[T, K comparable](q Query[T], keySelector func(T) K) map[K]int {
+	counts := make(map[K]int)
+	if q.fastSlice != nil {
+		for _, item := range q.fastSlice {
+			if q.fastWhere != nil && !q.fastWhere(item) {
+				continue
+			}
+			counts[keySelector(item)]++
+		}
+		return counts
+	}
+	for item := range q.iterate {
+		counts[keySelector(item)]++
+	}
+	return counts
+}
+
+// countItems 统计序列中每个元素的次数
+func countItems[T comparable](q Query[T]) map[T]int {
+	return CountBy(q, func(item T) T { return item })
+}
+
+// ExceptAll 多重集合差集：q1 中每个元素按 q2 中的次数依次抵消，保留 q1 的顺序
+func ExceptAll[T comparable](q1, q2 Query[T]) Query[T] {
+	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
+		iterate: func(yield func(T) bool) {
+			counts := countItems(q2)
+			for item := range q1.Seq() {
+				if counts[item] > 0 {
+					counts[item]--
+					continue
+				}
+				if !yield(item) {
+					return
+				}
+			}
+		},
+		capacity: q1.capacity,
+	}
+}
+
+// IntersectAll 多重集合交集：每个元素保留两者中较小的次数，保留 q1 的顺序
+func IntersectAll[T comparable](q1, q2 Query[T]) Query[T] {
+	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
+		iterate: func(yield func(T) bool) {
+			counts := countItems(q2)
+			for item := range q1.Seq() {
+				if counts[item] == 0 {
+					continue
+				}
+				counts[item]--
+				if !yield(item) {
+					return
+				}
+			}
+		},
+		capacity: q1.capacity,
+	}
+}
+
+// UnionAll 多重集合并集：每个元素保留两者中较大的次数，先输出 q1 全部元素再输出 q2 超出的部分；
+// 次数相加的并集请使用 Concat
+func UnionAll[T comparable](q1, q2 Query[T]) Query[T] {
+	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
+		iterate: func(yield func(T) bool) {
+			counts := make(map[T]int, q1.capacity)
+			for item := range q1.Seq() {
+				counts[item]++
+				if !yield(item) {
+					return
+				}
+			}
+			for item := range q2.Seq() {
+				if counts[item] > 0 {
+					counts[item]--
+					continue
+				}
+				if !yield(item) {
+					return
+				}
+			}
+		},
+		capacity: q1.capacity + q2.capacity,
+	}
+}
@@ -0,0 +1,98 @@
+package linq
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestBagBasic 测试多重集合基本操作
+func TestBagBasic(t *testing.T) {
+	b := NewBag("a", "b", "a", "c", "a")
+	if b.Len() != 5 || b.Unique() != 3 || b.Count("a") != 3 {
+		t.Errorf("计数错误: len=%d unique=%d a=%d", b.Len(), b.Unique(), b.Count("a"))
+	}
+	if got := FromBag(b).ToSlice(); !slices.Equal(got, []string{"a", "a", "a", "b", "c"}) {
+		t.Errorf("FromBag 期望按首次加入顺序重复输出，实际得到 %v", got)
+	}
+	if n := b.RemoveN("a", 2); n != 2 || b.Count("a") != 1 {
+		t.Errorf("RemoveN 期望移除 2 个，实际 %d 个", n)
+	}
+	if n := b.RemoveN("b", 5); n != 1 || b.Has("b") || b.Unique() != 2 {
+		t.Errorf("RemoveN 超量时应移除全部，实际 %d 个", n)
+	}
+	if n := b.Remove("x", "c"); n != 1 || b.Len() != 1 {
+		t.Errorf("Remove 期望移除 1 个，实际 %d 个", n)
+	}
+	b.AddN("z", 0)
+	if b.Has("z") {
+		t.Errorf("AddN(0) 不应添加元素")
+	}
+
+	var zero Bag[int]
+	zero.AddN(7, 3)
+	if zero.Count(7) != 3 || zero.Len() != 3 {
+		t.Errorf("零值多重集合应可直接使用")
+	}
+}
+
+// TestBagAlgebra 测试多重集合运算
+func TestBagAlgebra(t *testing.T) {
+	a := NewBag(1, 1, 1, 2, 3)
+	b := NewBag(1, 2, 2, 4)
+
+	check := func(name string, got *Bag[int], want []int) {
+		t.Helper()
+		if !got.Equal(NewBag(want...)) {
+			t.Errorf("%s 期望 %v，实际得到 %v", name, want, FromBag(got).ToSlice())
+		}
+	}
+	check("Sum", a.Sum(b), []int{1, 1, 1, 1, 2, 2, 2, 3, 4})
+	check("Union", a.Union(b), []int{1, 1, 1, 2, 2, 3, 4})
+	check("Intersect", a.Intersect(b), []int{1, 2})
+	check("Difference", a.Difference(b), []int{1, 1, 3})
+
+	if !NewBag(1, 1).IsSubset(a) || NewBag(2, 2).IsSubset(a) {
+		t.Errorf("IsSubset 错误")
+	}
+	if !ToBag(From([]int{3, 1, 2, 1, 1})).Equal(a) || a.Equal(b) {
+		t.Errorf("Equal 错误")
+	}
+}
+
+// TestMultisetOperators 测试多重集合查询运算
+func TestMultisetOperators(t *testing.T) {
+	inventory := From([]string{"apple", "apple", "pear", "apple", "plum"})
+	returned := From([]string{"apple", "plum", "plum"})
+
+	if got := inventory.ExceptAll(returned).ToSlice(); !slices.Equal(got, []string{"apple", "pear", "apple"}) {
+		t.Errorf("ExceptAll 错误: %v", got)
+	}
+	if got := inventory.IntersectAll(returned).ToSlice(); !slices.Equal(got, []string{"apple", "plum"}) {
+		t.Errorf("IntersectAll 错误: %v", got)
+	}
+	if got := inventory.UnionAll(returned).ToSlice(); !slices.Equal(got, []string{"apple", "apple", "pear", "apple", "plum", "plum"}) {
+		t.Errorf("UnionAll 错误: %v", got)
+	}
+	// 与 Bag 运算结果一致
+	a, b := ToBag(inventory), ToBag(returned)
+	if !ToBag(ExceptAll(inventory, returned)).Equal(a.Difference(b)) ||
+		!ToBag(IntersectAll(inventory, returned)).Equal(a.Intersect(b)) ||
+		!ToBag(UnionAll(inventory, returned)).Equal(a.Union(b)) {
+		t.Errorf("查询运算与 Bag 运算结果不一致")
+	}
+	if got := ExceptAll(inventory, returned).Take(1).ToSlice(); !slices.Equal(got, []string{"apple"}) {
+		t.Errorf("Take 错误: %v", got)
+	}
+}
+
+// TestCountBy 测试按键计数
+func TestCountBy(t *testing.T) {
+	counts := CountBy(From(members), func(m *BMember) int8 { return m.Sex })
+	if counts[1] != 2 || counts[2] != 2 || len(counts) != 2 {
+		t.Errorf("CountBy 错误: %v", counts)
+	}
+	counts2 := CountBy(QueryRange(0, 10).Where(func(i int) bool { return i > 2 }), func(i int) bool { return i%2 == 0 })
+	if counts2[true] != 3 || counts2[false] != 4 {
+		t.Errorf("CountBy 迭代器路径错误: %v", counts2)
+	}
+}
@@ -0,0 +1,153 @@
+package linq
+
+import (
+	"context"
+	"hash/maphash"
+	"sync"
+)
+
+// 通道扇入扇出：MergeChannels 合并多个通道，Partition 按键分流，Broadcast 复制到多个消费者。
+// 后台 goroutine 均在完成或上下文取消后退出并关闭输出通道；Partition、Broadcast 同时返回 stop，
+// 消费者提前放弃读取时调用 stop（或取消 WithContext 绑定的上下文），否则生产者会因背压一直阻塞。
+// stop 只发出取消信号、不等待：生产者若正阻塞在上游（如 Where 之后的通道源）等待元素，
+// 在上游产生下一个元素或结束时退出；上游直接是 FromChannel 源时立即退出。
+
+// partitionSeed Partition 的哈希种子，同一进程内相同的键总是分到同一通道
+var partitionSeed = maphash.MakeSeed()
+
+// MergeChannels 合并多个通道，按到达顺序输出，全部通道关闭或 ctx 取消后结束；
+// 每次遍历启动转发 goroutine，遍历提前结束时取消并等待其退出。ctx 为 nil 时使用 context.Background()
+func MergeChannels[T comparable](ctx context.Context, chans ...<-chan T) Query[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Query[T]{
+		ctx: ctx,
+		iterate: func(yield func(T) bool) {
+			mergeCtx, cancel := context.WithCancel(ctx)
+			out := make(chan T)
+			var wg sync.WaitGroup
+			for _, ch := range chans {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						select {
+						case <-mergeCtx.Done():
+							return
+						case item, ok := <-ch:
+							if !ok {
+								return
+							}
+							select {
+							case <-mergeCtx.Done():
+								return
+							case out <- item:
+							}
+						}
+					}
+				}()
+			}
+			go func() {
+				wg.Wait()
+				close(out)
+			}()
+			defer func() {
+				cancel()
+				for range out {
+					// 等待转发 goroutine 全部退出
+				}
+			}()
+			for item := range out {
+				if !yield(item) {
+					return
+				}
+			}
+		},
+	}
+}
+
+// Partition 按 keyFn 将元素分流到 n 个通道，相同键的元素总在同一通道并保持原有顺序；
+// 任一通道未被读取时其余通道也会阻塞，遍历完成、上下文取消或调用 stop 后关闭全部通道。
+// stop 取消后立即返回，可多次调用
+func Partition[T, K comparable](q Query[T], n int, keyFn func(T) K) ([]<-chan T, func()) {
+	n = max(n, 1)
+	ctx, cancel := context.WithCancel(firstContext(q.ctx, context.Background()))
+	outs := make([]chan T, n)
+	result := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		result[i] = outs[i]
+	}
+	go func() {
+		defer func() {
+			for _, ch := range outs {
+				close(ch)
+			}
+		}()
+		for item := range q.WithContext(ctx).Seq() {
+			i := maphash.Comparable(partitionSeed, keyFn(item)) % uint64(n)
+			select {
+			case <-ctx.Done():
+				return
+			case outs[i] <- item:
+			}
+		}
+	}()
+	return result, cancel
+}
+
+// Broadcast 将每个元素发送到全部 n 个通道，所有消费者都收到当前元素后才发送下一个（背压），
+// 各通道的读取顺序不受限制；遍历完成、上下文取消或调用 stop 后关闭全部通道。
+// stop 取消后立即返回，可多次调用
+func Broadcast[T comparable](q Query[T], n int) ([]<-chan T, func()) {
+	n = max(n, 1)
+	ctx, cancel := context.WithCancel(firstContext(q.ctx, context.Background()))
+	outs := make([]chan T, n)
+	result := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		result[i] = outs[i]
+	}
+	go func() {
+		// 每个消费者一个转发 goroutine，生产者等待全部转发完成后再取下一个元素
+		ins := make([]chan T, n)
+		acks := make(chan struct{}, n)
+		var wg sync.WaitGroup
+		for i := range ins {
+			ins[i] = make(chan T, 1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer close(outs[i])
+				for item := range ins[i] {
+					select {
+					case <-ctx.Done():
+						return
+					case outs[i] <- item:
+						acks <- struct{}{}
+					}
+				}
+			}()
+		}
+		defer func() {
+			for _, in := range ins {
+				close(in)
+			}
+			wg.Wait()
+		}()
+		for item := range q.WithContext(ctx).Seq() {
+			for _, in := range ins {
+				in <- item
+			}
+			for range n {
+				select {
+				case <-ctx.Done():
+					return
+				case <-acks:
+				}
+			}
+		}
+	}()
+	return result, cancel
+}
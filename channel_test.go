@@ -0,0 +1,358 @@
+package linq
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// checkGoroutines 记录当前 goroutine 数，返回的函数确认后台 goroutine 均已退出
+func checkGoroutines(t *testing.T) func() {
+	t.Helper()
+	base := runtime.NumGoroutine()
+	return func() {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for runtime.NumGoroutine() > base {
+			if time.Now().After(deadline) {
+				t.Errorf("goroutine 泄漏：期望不超过 %d 个，实际 %d 个", base, runtime.NumGoroutine())
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+// sendAll 在后台发送元素后关闭通道
+func sendAll(items ...int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for _, item := range items {
+			ch <- item
+		}
+	}()
+	return ch
+}
+
+// TestMergeChannels 合并多个通道，提前结束或取消时转发 goroutine 退出
+func TestMergeChannels(t *testing.T) {
+	defer checkGoroutines(t)()
+	got := MergeChannels(context.Background(), sendAll(1, 2, 3), sendAll(4, 5), sendAll()).ToSlice()
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("期望合并得到 [1 2 3 4 5]，实际得到 %v", got)
+	}
+
+	// 提前结束：未关闭的通道不导致泄漏
+	never := make(chan int)
+	if first := MergeChannels(nil, never, sendAll(9)).First(); first != 9 {
+		t.Errorf("期望首个元素 9，实际得到 %d", first)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := MergeChannels(ctx, never)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if n, err := q.CountErr(); n != 0 || err != context.Canceled {
+		t.Errorf("期望取消后返回 0 与 context.Canceled，实际得到 %d 与 %v", n, err)
+	}
+}
+
+// TestToChannelN 缓冲通道在消费前即可容纳 size 个元素
+func TestToChannelN(t *testing.T) {
+	defer checkGoroutines(t)()
+	ch := QueryRange(1, 5).ToChannelN(context.Background(), 3)
+	deadline := time.Now().Add(2 * time.Second)
+	for len(ch) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(ch) != 3 || cap(ch) != 3 {
+		t.Errorf("期望缓冲 3 个元素，实际 %d / %d", len(ch), cap(ch))
+	}
+	var got []int
+	for item := range ch {
+		got = append(got, item)
+	}
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("期望 [1 2 3 4 5]，实际得到 %v", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	QueryRepeat(1, 1_000_000).ToChannelN(ctx, 2)
+	cancel()
+}
+
+// TestPartition 相同键的元素进入同一通道且保持顺序
+func TestPartition(t *testing.T) {
+	defer checkGoroutines(t)()
+	chans, stop := Partition(QueryRange(0, 100), 4, func(i int) int { return i % 10 })
+	defer stop()
+	if len(chans) != 4 {
+		t.Fatalf("期望 4 个通道，实际 %d 个", len(chans))
+	}
+	results := make([][]int, len(chans))
+	var wg sync.WaitGroup
+	for i, ch := range chans {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range ch {
+				results[i] = append(results[i], item)
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	owner := map[int]int{}
+	for i, items := range results {
+		total += len(items)
+		if !slices.IsSorted(items) {
+			t.Errorf("通道 %d 期望保持原有顺序，实际得到 %v", i, items)
+		}
+		for _, item := range items {
+			if prev, ok := owner[item%10]; ok && prev != i {
+				t.Errorf("键 %d 出现在通道 %d 与 %d", item%10, prev, i)
+			}
+			owner[item%10] = i
+		}
+	}
+	if total != 100 {
+		t.Errorf("期望共 100 个元素，实际 %d 个", total)
+	}
+
+	// 消费者放弃读取时取消上下文，阻塞的生产者退出并关闭全部通道
+	ctx, cancel := context.WithCancel(context.Background())
+	chans, _ = Partition(QueryRange(0, 1000).WithContext(ctx), 3, func(i int) int { return i })
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	for _, ch := range chans {
+		for range ch {
+		}
+	}
+}
+
+// TestBroadcast 每个消费者收到全部元素，读取顺序不受限制
+func TestBroadcast(t *testing.T) {
+	defer checkGoroutines(t)()
+	chans, stop := Broadcast(QueryRange(1, 3), 2)
+	defer stop()
+	// 同一 goroutine 先读第二个通道再读第一个通道
+	var first, second []int
+	for range 3 {
+		second = append(second, <-chans[1])
+		first = append(first, <-chans[0])
+	}
+	if _, ok := <-chans[0]; ok {
+		t.Error("期望遍历完成后关闭通道")
+	}
+	if !slices.Equal(first, []int{1, 2, 3}) || !slices.Equal(second, []int{1, 2, 3}) {
+		t.Errorf("期望两个消费者都收到 [1 2 3]，实际得到 %v 与 %v", first, second)
+	}
+
+	// 背压：一个消费者未读取时不发送下一个元素
+	ctx, cancel := context.WithCancel(context.Background())
+	produced := 0
+	chans, _ = Broadcast(QueryRepeat(1, 1_000_000).WithContext(ctx).Where(func(int) bool {
+		produced++
+		return true
+	}), 2)
+	<-chans[0]
+	<-chans[1]
+	<-chans[0]
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	for _, ch := range chans {
+		for range ch {
+		}
+	}
+	if produced > 3 {
+		t.Errorf("期望背压限制生产，实际生产 %d 个", produced)
+	}
+}
+
+// TestPartitionBroadcastStop 未绑定上下文时消费者放弃读取，stop 使全部后台 goroutine 退出
+func TestPartitionBroadcastStop(t *testing.T) {
+	defer checkGoroutines(t)()
+	chans, stop := Partition(QueryRepeat(1, 1_000_000), 2, func(i int) int { return i })
+	// 全部元素的键相同，从任一通道读取一次后放弃
+	select {
+	case <-chans[0]:
+	case <-chans[1]:
+	}
+	stop()
+	stop()
+	for _, ch := range chans {
+		for range ch {
+		}
+	}
+
+	chans, stop = Broadcast(QueryRepeat(1, 1_000_000), 3)
+	<-chans[1]
+	stop()
+	for _, ch := range chans {
+		for range ch {
+		}
+	}
+
+	// 上游通道阻塞时 stop 后通道关闭，经过其他操作的通道源在产生下一个元素时退出
+	src := make(chan int)
+	defer close(src)
+	chans, stop = Partition(FromChannel(src), 2, func(i int) int { return i })
+	time.Sleep(10 * time.Millisecond)
+	stop()
+	for _, ch := range chans {
+		for range ch {
+		}
+	}
+	chans, stop = Broadcast(FromChannel(src).Where(func(i int) bool { return i > 0 }), 2)
+	time.Sleep(10 * time.Millisecond)
+	stop()
+	src <- 1
+	for _, ch := range chans {
+		for range ch {
+		}
+	}
+}
+
+// TestToChannelStop 消费者停止读取后调用 stop，生产者退出并关闭通道
+func TestToChannelStop(t *testing.T) {
+	defer checkGoroutines(t)()
+	ch, stop := QueryRepeat(1, 1_000_000).ToChannelStop(context.Background())
+	<-ch
+	<-ch
+	stop()
+	stop()
+	// stop 后至多再收到一个元素，随后通道关闭
+	n := 0
+	for range ch {
+		n++
+	}
+	if n > 1 {
+		t.Errorf("期望 stop 后至多收到 1 个元素，实际得到 %d 个", n)
+	}
+
+	ch, stop = From([]int{1, 2}).ToChannelStop(nil)
+	var got []int
+	for item := range ch {
+		got = append(got, item)
+	}
+	stop()
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("期望 [1 2]，实际得到 %v", got)
+	}
+}
+
+// TestToChannelStopBlockedUpstream 生产者阻塞在上游通道接收时 stop 立即返回，并通知上游停止
+func TestToChannelStopBlockedUpstream(t *testing.T) {
+	defer checkGoroutines(t)()
+	src := make(chan int, 1)
+	src <- 1
+	canceled := make(chan struct{})
+	ch, stop := FromChannelCancel(src, func() { close(canceled) }).ToChannelStop(context.Background())
+	if got := <-ch; got != 1 {
+		t.Fatalf("期望 1，实际得到 %d", got)
+	}
+	stop()
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("期望 stop 后调用上游的取消回调")
+	}
+	if _, ok := <-ch; ok {
+		t.Error("期望 stop 后通道关闭")
+	}
+}
+
+// TestToChannelStopBehindOperator 通道源之后接其他操作时 stop 不会挂起，上游产生下一个元素后生产者退出
+func TestToChannelStopBehindOperator(t *testing.T) {
+	defer checkGoroutines(t)()
+	src := make(chan int)
+	defer close(src)
+	ch, stop := FromChannel(src).Where(func(i int) bool { return i%2 == 0 }).ToChannelStop(context.Background())
+	src <- 2
+	if got := <-ch; got != 2 {
+		t.Fatalf("期望 2，实际得到 %d", got)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("上游空闲时 stop 未返回")
+	}
+	// 生产者取到下一个元素后发现已取消，不再发送并关闭通道
+	src <- 4
+	if _, ok := <-ch; ok {
+		t.Error("期望 stop 后不再发送元素并关闭通道")
+	}
+}
+
+// counter 持续发送递增整数直到 quit 关闭，返回通道、取消回调及已退出信号
+func counter() (<-chan int, func(), <-chan struct{}) {
+	ch := make(chan int)
+	quit := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		defer close(ch)
+		for i := 0; ; i++ {
+			select {
+			case <-quit:
+				return
+			case ch <- i:
+			}
+		}
+	}()
+	return ch, func() { close(quit) }, exited
+}
+
+// TestFromChannelCancel 提前结束时调用取消回调，上游生产者退出
+func TestFromChannelCancel(t *testing.T) {
+	defer checkGoroutines(t)()
+	ch, cancel, exited := counter()
+	if got := FromChannelCancel(ch, cancel).Take(3).ToSlice(); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("期望 [0 1 2]，实际得到 %v", got)
+	}
+	<-exited
+
+	calls := 0
+	ch, cancel, exited = counter()
+	q := FromChannelCancel(ch, func() {
+		calls++
+		cancel()
+	})
+	q.First()
+	q.First() // 再次提前结束不重复调用
+	<-exited
+	if calls != 1 {
+		t.Errorf("期望取消回调调用 1 次，实际 %d 次", calls)
+	}
+
+	// 上下文取消时调用取消回调
+	ch, cancel, exited = counter()
+	ctx, stop := context.WithCancel(context.Background())
+	n, err := FromChannelCancel(ch, cancel).WithContext(ctx).Where(func(i int) bool {
+		if i == 5 {
+			stop()
+		}
+		return true
+	}).CountErr()
+	<-exited
+	if err != context.Canceled || n > 6 {
+		t.Errorf("期望取消后停止，实际得到 %d 与 %v", n, err)
+	}
+
+	// 通道关闭时不调用
+	called := false
+	if got := FromChannelCancel(sendAll(1, 2), func() { called = true }).ToSlice(); called || !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("通道关闭时期望不调用取消回调并得到 [1 2]，实际得到 %v，调用 %v", got, called)
+	}
+}
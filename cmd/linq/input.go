@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/livexy/linq"
+)
+
+// row 一条记录；keys 非空时为输出字段顺序（来自 CSV 表头或 select），否则按字段名排序
+type row struct {
+	fields map[string]any
+	keys   []string
+}
+
+// source 将输入流惰性解析为 Query，解析错误记录在 err 中
+type source struct {
+	format  string
+	readers []io.Reader
+	err     error
+}
+
+func newSource(format string, readers []io.Reader) (*source, error) {
+	switch format {
+	case "json", "ndjson", "csv":
+		return &source{format: format, readers: readers}, nil
+	}
+	return nil, fmt.Errorf("未知的输入格式 %q", format)
+}
+
+// query 依次拼接各输入的记录
+func (s *source) query() linq.Query[*row] {
+	q := linq.QueryEmpty[*row]()
+	for _, r := range s.readers {
+		if s.format == "csv" {
+			q = q.Concat(linq.Generate(s.csvRows(r)))
+		} else {
+			q = q.Concat(linq.Generate(s.jsonRows(r)))
+		}
+	}
+	return q
+}
+
+func (s *source) fail(err error) (*row, bool) {
+	if s.err == nil {
+		s.err = err
+	}
+	return nil, false
+}
+
+// jsonRows 逐个解析 JSON 值，首个值为数组时逐个输出数组元素
+func (s *source) jsonRows(r io.Reader) func() (*row, bool) {
+	br := bufio.NewReader(r)
+	dec := json.NewDecoder(br)
+	dec.UseNumber()
+	started, inArray := false, false
+	return func() (*row, bool) {
+		if s.err != nil {
+			return nil, false
+		}
+		if !started {
+			started = true
+			if b, err := peekNonSpace(br); err == nil && b == '[' {
+				if _, err := dec.Token(); err != nil {
+					return s.fail(err)
+				}
+				inArray = true
+			}
+		}
+		if inArray && !dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return s.fail(err)
+			}
+			inArray = false
+		}
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			if errors.Is(err, io.EOF) && !inArray {
+				return nil, false
+			}
+			return s.fail(err)
+		}
+		if m, ok := v.(map[string]any); ok {
+			return &row{fields: m}, true
+		}
+		return &row{fields: map[string]any{"value": v}}, true
+	}
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
+
+// csvRows 首行为表头，合法 JSON 数字的值转为 json.Number
+func (s *source) csvRows(r io.Reader) func() (*row, bool) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	var header []string
+	return func() (*row, bool) {
+		if s.err != nil {
+			return nil, false
+		}
+		if header == nil {
+			h, err := cr.Read()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil, false
+				}
+				return s.fail(err)
+			}
+			header = h
+		}
+		record, err := cr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, false
+			}
+			return s.fail(err)
+		}
+		fields := make(map[string]any, len(header))
+		for i, name := range header {
+			var v any
+			if i < len(record) {
+				v = record[i]
+				if _, err := strconv.ParseFloat(record[i], 64); err == nil && json.Valid([]byte(record[i])) {
+					v = json.Number(record[i])
+				}
+			}
+			fields[name] = v
+		}
+		return &row{fields: fields, keys: header}, true
+	}
+}
@@ -0,0 +1,103 @@
+// linq 命令行工具：以查询引擎处理 NDJSON / JSON / CSV 数据，类似 jq。
+//
+// 用法：
+//
+//	linq [-i json|csv] [-o ndjson|json|table] [-f file]... stage...
+//
+// json 输入可为 NDJSON（每行一个值）、多个连续的 JSON 值或单个 JSON 数组，非对象值包装为 {"value": v}；
+// csv 输入首行为表头，数值列自动识别为数字。指定多个文件时依次拼接。
+//
+// 阶段按顺序组成管道，每个阶段为关键字加参数：
+//
+//	where 'level == "error" && status >= 500'   过滤，运算符 == != > >= < <= ~（包含）
+//	select ts,msg,user.id                        保留字段，支持 a.b 嵌套路径
+//	orderby -status,ts                           排序，- 前缀为降序
+//	distinct                                     按整条记录去重
+//	skip N / take N                              跳过 / 取前 N 条
+//	groupby FIELD                                分组，后续 count / sum 按组计算，否则输出每组记录数
+//	count / sum FIELD                            终结操作，输出数值（分组后输出每组结果）
+//
+// 示例：
+//
+//	linq -f app.log where 'level == "error"' groupby service count
+//	cat users.csv | linq -i csv -o table orderby -age take 10
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "linq:", err)
+		var usage usageError
+		if errors.As(err, &usage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+// usageError 参数错误
+type usageError struct{ error }
+
+type fileList []string
+
+func (f *fileList) String() string     { return fmt.Sprint(*f) }
+func (f *fileList) Set(v string) error { *f = append(*f, v); return nil }
+
+// run 解析参数并执行管道，便于测试
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("linq", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	inFormat := fs.String("i", "json", "输入格式：json（含 NDJSON）或 csv")
+	outFormat := fs.String("o", "ndjson", "输出格式：ndjson、json 或 table")
+	var files fileList
+	fs.Var(&files, "f", "输入文件，可重复指定，默认读取标准输入")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, "用法: linq [-i json|csv] [-o ndjson|json|table] [-f file]... stage...")
+			fs.SetOutput(stdout)
+			fs.PrintDefaults()
+			return nil
+		}
+		return usageError{err}
+	}
+	stages, err := parseStages(fs.Args())
+	if err != nil {
+		return usageError{err}
+	}
+	out, err := newWriter(*outFormat, stdout)
+	if err != nil {
+		return usageError{err}
+	}
+
+	readers := []io.Reader{stdin}
+	if len(files) > 0 {
+		readers = readers[:0]
+		for _, name := range files {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			readers = append(readers, f)
+		}
+	}
+	src, err := newSource(*inFormat, readers)
+	if err != nil {
+		return usageError{err}
+	}
+
+	if err := execute(src.query(), stages, out); err != nil {
+		return err
+	}
+	if err := src.err; err != nil {
+		return err
+	}
+	return out.w.Flush()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const logs = `{"ts":3,"level":"error","service":"api","status":502,"user":{"id":7}}
+{"ts":1,"level":"info","service":"api","status":200,"user":{"id":8}}
+{"ts":2,"level":"error","service":"db","status":500,"user":{"id":7}}
+{"ts":4,"level":"error","service":"api","status":404}
+`
+
+func runCLI(t *testing.T, input string, args ...string) string {
+	t.Helper()
+	var out strings.Builder
+	if err := run(args, strings.NewReader(input), &out); err != nil {
+		t.Fatalf("%v 执行失败: %v", args, err)
+	}
+	return out.String()
+}
+
+func TestPipeline(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"透传按键排序", []string{"take", "1"},
+			`{"level":"error","service":"api","status":502,"ts":3,"user":{"id":7}}` + "\n"},
+		{"过滤投影排序", []string{"where", `level == "error" && status >= 500`, "orderby", "-ts", "select", "ts,user.id"},
+			"{\"ts\":3,\"user.id\":7}\n{\"ts\":2,\"user.id\":7}\n"},
+		{"包含", []string{"where", "service ~ d", "select", "service"},
+			"{\"service\":\"db\"}\n"},
+		{"缺失字段为 null", []string{"where", "user.id == null", "select", "ts"},
+			"{\"ts\":4}\n"},
+		{"多键排序", []string{"orderby", "service,-status", "select", "service,status"},
+			"{\"service\":\"api\",\"status\":502}\n{\"service\":\"api\",\"status\":404}\n{\"service\":\"api\",\"status\":200}\n{\"service\":\"db\",\"status\":500}\n"},
+		{"分组计数", []string{"where", `level == "error"`, "groupby", "service", "count"},
+			"{\"service\":\"api\",\"count\":2}\n{\"service\":\"db\",\"count\":1}\n"},
+		{"分组求和后排序", []string{"groupby", "service", "sum", "status", "orderby", "-sum", "take", "1"},
+			"{\"service\":\"api\",\"sum\":1106}\n"},
+		{"去重", []string{"select", "level", "distinct", "count"}, "2\n"},
+		{"分页", []string{"skip", "1", "take", "2", "select", "ts"}, "{\"ts\":1}\n{\"ts\":2}\n"},
+		{"求和", []string{"where", "status < 500", "sum", "status"}, "604\n"},
+		{"类型不同不参与大小比较", []string{"where", `status > "a"`, "count"}, "0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runCLI(t, logs, tt.args...); got != tt.want {
+				t.Errorf("期望 %q，实际得到 %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONInput(t *testing.T) {
+	// 顶层数组、紧随其后的值及非对象值
+	got := runCLI(t, `[{"a":1},{"a":2}] 3 "x"`)
+	want := "{\"a\":1}\n{\"a\":2}\n{\"value\":3}\n{\"value\":\"x\"}\n"
+	if got != want {
+		t.Errorf("期望 %q，实际得到 %q", want, got)
+	}
+
+	var out strings.Builder
+	if err := run(nil, strings.NewReader(`{"a":1}{"a":`), &out); err == nil {
+		t.Error("期望截断的输入返回错误")
+	}
+}
+
+func TestCSVInput(t *testing.T) {
+	csv := "name,age,city\nbob,30,NYC\nann,25,LA\ncid,NaN,SF\n"
+	got := runCLI(t, csv, "-i", "csv", "where", "age > 26")
+	if want := "{\"name\":\"bob\",\"age\":30,\"city\":\"NYC\"}\n"; got != want {
+		t.Errorf("期望 %q，实际得到 %q", want, got)
+	}
+	got = runCLI(t, csv, "-i", "csv", "where", "age == NaN", "select", "name")
+	if want := "{\"name\":\"cid\"}\n"; got != want {
+		t.Errorf("非 JSON 数字应保留为字符串，期望 %q，实际得到 %q", want, got)
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	got := runCLI(t, logs, "-o", "json", "take", "2", "select", "ts")
+	if want := "[\n  {\"ts\":3},\n  {\"ts\":1}\n]\n"; got != want {
+		t.Errorf("json 期望 %q，实际得到 %q", want, got)
+	}
+	if got := runCLI(t, logs, "-o", "json", "take", "0"); got != "[]\n" {
+		t.Errorf("空 json 期望 %q，实际得到 %q", "[]\n", got)
+	}
+	got = runCLI(t, logs, "-o", "table", "orderby", "ts", "take", "2", "select", "ts,service,user.id")
+	want := "ts  service  user.id\n1   api      8\n2   db       7\n"
+	if got != want {
+		t.Errorf("table 期望 %q，实际得到 %q", want, got)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.ndjson"), filepath.Join(dir, "b.json")
+	os.WriteFile(a, []byte("{\"n\":1}\n{\"n\":2}\n"), 0o644)
+	os.WriteFile(b, []byte(`[{"n":3}]`), 0o644)
+	if got := runCLI(t, "", "-f", a, "-f", b, "sum", "n"); got != "6\n" {
+		t.Errorf("期望 6，实际得到 %q", got)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"bogus"},
+		{"take"},
+		{"take", "x"},
+		{"where", "status"},
+		{"select", ","},
+		{"count", "take", "1"},
+		{"-o", "xml"},
+		{"-i", "yaml"},
+		{"-x"},
+	} {
+		var out strings.Builder
+		err := run(args, strings.NewReader(logs), &out)
+		var usage usageError
+		if !errors.As(err, &usage) {
+			t.Errorf("%v 期望参数错误，实际得到 %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// writer 按输出格式写出记录；ndjson 与 json 逐条写出，table 需缓冲全部记录以对齐列宽
+type writer struct {
+	format string
+	w      *bufio.Writer
+	count  int
+	table  []*row
+}
+
+func newWriter(format string, stdout io.Writer) (*writer, error) {
+	switch format {
+	case "ndjson", "json", "table":
+		return &writer{format: format, w: bufio.NewWriter(stdout)}, nil
+	}
+	return nil, fmt.Errorf("未知的输出格式 %q", format)
+}
+
+func (o *writer) row(r *row) error {
+	defer func() { o.count++ }()
+	switch o.format {
+	case "table":
+		o.table = append(o.table, r)
+		return nil
+	case "json":
+		sep := ",\n  "
+		if o.count == 0 {
+			sep = "[\n  "
+		}
+		if _, err := o.w.WriteString(sep); err != nil {
+			return err
+		}
+		_, err := o.w.WriteString(encodeRow(r))
+		return err
+	}
+	_, err := o.w.WriteString(encodeRow(r) + "\n")
+	return err
+}
+
+// scalar 写出 count / sum 的结果
+func (o *writer) scalar(v any) error {
+	_, err := o.w.WriteString(canonical(v) + "\n")
+	return err
+}
+
+func (o *writer) close() error {
+	switch o.format {
+	case "json":
+		end := "\n]\n"
+		if o.count == 0 {
+			end = "[]\n"
+		}
+		_, err := o.w.WriteString(end)
+		return err
+	case "table":
+		return o.writeTable()
+	}
+	return nil
+}
+
+// writeTable 列为各记录字段的并集，按首次出现的顺序排列
+func (o *writer) writeTable() error {
+	var columns []string
+	seen := make(map[string]bool)
+	for _, r := range o.table {
+		for _, k := range r.order() {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	cells := make([]string, len(columns))
+	for _, r := range o.table {
+		for i, c := range columns {
+			cells[i] = cell(r.fields[c])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// cell 表格单元格文本，制表符及换行替换为空格
+func cell(v any) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(text(v))
+}
+
+// order 输出字段顺序
+func (r *row) order() []string {
+	if r.keys != nil {
+		return r.keys
+	}
+	keys := make([]string, 0, len(r.fields))
+	for k := range r.fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// encodeRow 按字段顺序编码为单行 JSON 对象
+func encodeRow(r *row) string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, k := range r.order() {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(canonical(k))
+		sb.WriteByte(':')
+		sb.WriteString(canonical(r.fields[k]))
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
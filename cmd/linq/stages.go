@@ -0,0 +1,399 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/livexy/linq"
+)
+
+// stage 管道中的一个阶段
+type stage struct {
+	op     string
+	where  []condition
+	fields []string // select / orderby 字段，groupby 分组字段为 fields[0]
+	desc   []bool   // orderby 各字段是否降序
+	n      int      // skip / take
+	agg    string   // groupby 之后的聚合：count 或 sum
+	field  string   // sum 字段
+}
+
+// parseStages 解析阶段参数
+func parseStages(args []string) ([]stage, error) {
+	var stages []stage
+	for i := 0; i < len(args); i++ {
+		op := args[i]
+		arg := func() (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("%s 缺少参数", op)
+			}
+			i++
+			return args[i], nil
+		}
+		s := stage{op: op}
+		switch op {
+		case "where":
+			expr, err := arg()
+			if err != nil {
+				return nil, err
+			}
+			if s.where, err = parseConditions(expr); err != nil {
+				return nil, err
+			}
+		case "select":
+			list, err := arg()
+			if err != nil {
+				return nil, err
+			}
+			s.fields = splitFields(list)
+		case "orderby":
+			list, err := arg()
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range splitFields(list) {
+				name, desc := strings.CutPrefix(f, "-")
+				s.fields = append(s.fields, name)
+				s.desc = append(s.desc, desc)
+			}
+		case "skip", "take":
+			v, err := arg()
+			if err != nil {
+				return nil, err
+			}
+			if s.n, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("%s 的参数须为整数: %q", op, v)
+			}
+		case "groupby":
+			field, err := arg()
+			if err != nil {
+				return nil, err
+			}
+			s.fields = []string{field}
+			s.agg = "count"
+			if i+1 < len(args) && (args[i+1] == "count" || args[i+1] == "sum") {
+				i++
+				if s.agg = args[i]; s.agg == "sum" {
+					if s.field, err = arg(); err != nil {
+						return nil, err
+					}
+				}
+			}
+		case "sum":
+			field, err := arg()
+			if err != nil {
+				return nil, err
+			}
+			s.field = field
+		case "distinct", "count":
+		default:
+			return nil, fmt.Errorf("未知的阶段 %q", op)
+		}
+		if len(stages) > 0 {
+			if last := stages[len(stages)-1].op; last == "count" || last == "sum" {
+				return nil, fmt.Errorf("%s 之后不能再接 %s", last, op)
+			}
+		}
+		if (op == "select" || op == "orderby") && len(s.fields) == 0 {
+			return nil, fmt.Errorf("%s 缺少字段", op)
+		}
+		stages = append(stages, s)
+	}
+	return stages, nil
+}
+
+func splitFields(list string) []string {
+	var fields []string
+	for _, f := range strings.Split(list, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// execute 将阶段依次应用到查询上并输出结果
+func execute(q linq.Query[*row], stages []stage, out *writer) error {
+	for _, s := range stages {
+		switch s.op {
+		case "where":
+			conds := s.where
+			q = q.Where(func(r *row) bool {
+				for _, c := range conds {
+					if !c.match(r) {
+						return false
+					}
+				}
+				return true
+			})
+		case "select":
+			fields := s.fields
+			q = linq.Select(q, func(r *row) *row {
+				selected := &row{fields: make(map[string]any, len(fields)), keys: fields}
+				for _, f := range fields {
+					selected.fields[f] = r.get(f)
+				}
+				return selected
+			})
+		case "orderby":
+			oq := q.Order(fieldComparator(s.fields[0], s.desc[0]))
+			for i := 1; i < len(s.fields); i++ {
+				oq = oq.Then(fieldComparator(s.fields[i], s.desc[i]))
+			}
+			q = oq.Query
+		case "distinct":
+			q = linq.DistinctBy(q, func(r *row) string { return canonical(r.fields) })
+		case "skip":
+			q = q.Skip(s.n)
+		case "take":
+			q = q.Take(s.n)
+		case "groupby":
+			q = groupRows(q, s)
+		case "count":
+			return out.scalar(q.Count())
+		case "sum":
+			return out.scalar(sumField(q, s.field))
+		}
+	}
+	for r := range q.Seq() {
+		if err := out.row(r); err != nil {
+			return err
+		}
+	}
+	return out.close()
+}
+
+// groupRows 按字段分组，每组输出 {字段: 键, count|sum: 值}，按键升序排列
+func groupRows(q linq.Query[*row], s stage) linq.Query[*row] {
+	key := s.fields[0]
+	groups := linq.GroupBy(q, func(r *row) string { return canonical(r.get(key)) })
+	rows := linq.Select(groups, func(g *linq.KV[string, []*row]) *row {
+		r := &row{fields: map[string]any{key: g.Value[0].get(key)}, keys: []string{key, s.agg}}
+		if s.agg == "sum" {
+			r.fields["sum"] = sumField(linq.From(g.Value), s.field)
+		} else {
+			r.fields["count"] = len(g.Value)
+		}
+		return r
+	})
+	return rows.Order(fieldComparator(key, false)).Query
+}
+
+// sumField 对字段的数值求和，非数值忽略
+func sumField(q linq.Query[*row], field string) any {
+	sum := linq.SumBy(q, func(r *row) float64 {
+		if f, ok := toNumber(r.get(field)); ok {
+			return f
+		}
+		return 0
+	})
+	return number(sum)
+}
+
+// number 整数值以整数形式输出
+func number(f float64) any {
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f)
+	}
+	return f
+}
+
+// get 按 a.b 路径取值，不存在时为 nil
+func (r *row) get(path string) any {
+	if v, ok := r.fields[path]; ok {
+		return v
+	}
+	var cur any = r.fields
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[part]
+	}
+	return cur
+}
+
+// condition where 中的单个比较条件
+type condition struct {
+	field string
+	op    string
+	value any
+}
+
+var operators = []string{"==", "!=", ">=", "<=", ">", "<", "~"}
+
+// parseConditions 解析以 && 连接的条件，值为 JSON 字面量或裸字符串
+func parseConditions(expr string) ([]condition, error) {
+	var conds []condition
+	for _, part := range splitOutsideQuotes(expr, "&&") {
+		part = strings.TrimSpace(part)
+		c, ok := condition{}, false
+		for i := 0; i < len(part) && !ok; i++ {
+			if part[i] == '"' {
+				break
+			}
+			for _, op := range operators {
+				if strings.HasPrefix(part[i:], op) {
+					c.field, c.op = strings.TrimSpace(part[:i]), op
+					c.value = parseLiteral(strings.TrimSpace(part[i+len(op):]))
+					ok = true
+					break
+				}
+			}
+		}
+		if !ok || c.field == "" {
+			return nil, fmt.Errorf("无法解析条件 %q，格式为 字段 运算符 值", part)
+		}
+		conds = append(conds, c)
+	}
+	return conds, nil
+}
+
+// splitOutsideQuotes 按分隔符拆分，忽略双引号内的分隔符
+func splitOutsideQuotes(s, sep string) []string {
+	var parts []string
+	quoted, start := false, 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quoted:
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseLiteral(s string) any {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err == nil && !dec.More() {
+		return v
+	}
+	return s
+}
+
+func (c condition) match(r *row) bool {
+	v := r.get(c.field)
+	switch c.op {
+	case "~":
+		return strings.Contains(text(v), text(c.value))
+	case "==":
+		return compareValues(v, c.value) == 0
+	case "!=":
+		return compareValues(v, c.value) != 0
+	}
+	// 大小比较仅在同类值之间成立
+	if rank(v) != rank(c.value) {
+		return false
+	}
+	r0 := compareValues(v, c.value)
+	switch c.op {
+	case ">":
+		return r0 > 0
+	case ">=":
+		return r0 >= 0
+	case "<":
+		return r0 < 0
+	}
+	return r0 <= 0
+}
+
+// fieldComparator 按字段值比较，desc 为 true 时降序
+func fieldComparator(field string, desc bool) linq.CompareFunc[*row] {
+	return func(a, b *row) int {
+		if desc {
+			return compareValues(b.get(field), a.get(field))
+		}
+		return compareValues(a.get(field), b.get(field))
+	}
+}
+
+// rank 值的类别顺序：null < bool < number < string < 其他
+func rank(v any) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case json.Number, float64, int, int64:
+		return 2
+	case string:
+		return 3
+	}
+	return 4
+}
+
+// compareValues 先按类别再按值比较
+func compareValues(a, b any) int {
+	if r := cmp.Compare(rank(a), rank(b)); r != 0 {
+		return r
+	}
+	switch rank(a) {
+	case 1:
+		x, y := a.(bool), b.(bool)
+		if x == y {
+			return 0
+		} else if x {
+			return 1
+		}
+		return -1
+	case 2:
+		x, _ := toNumber(a)
+		y, _ := toNumber(b)
+		return cmp.Compare(x, y)
+	case 3:
+		return strings.Compare(a.(string), b.(string))
+	case 4:
+		return strings.Compare(canonical(a), canonical(b))
+	}
+	return 0
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// text 值的文本形式：字符串原样，其余为 JSON
+func text(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if v == nil {
+		return ""
+	}
+	return canonical(v)
+}
+
+// canonical 值的规范 JSON 文本，对象按键排序，用作去重及分组的键
+func canonical(v any) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
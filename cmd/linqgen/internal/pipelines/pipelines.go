@@ -0,0 +1,87 @@
+// Package pipelines 是 linqgen 的示例与测试输入，生成结果见 pipelines_linqgen.go
+package pipelines
+
+import (
+	"strings"
+
+	"github.com/livexy/linq"
+)
+
+//go:generate go run github.com/livexy/linq/cmd/linqgen pipelines.go
+
+// User 示例数据
+type User struct {
+	Name   string
+	Age    int
+	Active bool
+}
+
+//linq:generate
+func sumEvenSquares(xs []int) int {
+	return linq.Sum(linq.Select(linq.From(xs).Where(func(x int) bool { return x%2 == 0 }),
+		func(x int) int { return x * x }))
+}
+
+//linq:generate activeNamesFast
+func activeNames(users []User, offset, limit int) []string {
+	return linq.Select(linq.From(users).Where(func(u User) bool { return u.Active }),
+		func(u User) string { return strings.ToUpper(u.Name) }).Skip(offset).Take(limit).ToSlice()
+}
+
+//linq:generate
+func countAdults(users []User) int {
+	return linq.From(users).Where(func(u User) bool { return u.Age >= 18 }).Count()
+}
+
+//linq:generate
+func anyNegative(xs []float64) bool {
+	return linq.From(xs).Where(func(x float64) bool { return x < 0 }).Any()
+}
+
+//linq:generate
+func allShort(words []string, n int) bool {
+	return linq.From(words).Take(n).All(func(w string) bool { return len(w) <= 5 })
+}
+
+func isPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//linq:generate
+func firstPrimes(start, count, k int) []int {
+	return linq.QueryRange(start, count).Where(isPrime).Take(k).ToSlice()
+}
+
+//linq:generate
+func sumScaled(xs []float64, factor float64) float64 {
+	return linq.Sum(linq.Select[float64, float64](linq.From(xs).Skip(1), func(x float64) float64 {
+		scaled := x * factor
+		return scaled
+	}))
+}
+
+// multipleOfCalls 记录 multipleOf 被调用的次数，用于确认生成代码只求值一次函数值
+var multipleOfCalls int
+
+func multipleOf(k int) func(int) bool {
+	multipleOfCalls++
+	return func(x int) bool { return x%k == 0 }
+}
+
+//linq:generate
+func countMultiples(xs []int, k int) int {
+	return linq.From(xs).Where(multipleOf(k)).Count()
+}
+
+//linq:generate
+func ones(xs []int) []float64 {
+	return linq.Select(linq.From(xs), func(x int) float64 { return 1 }).ToSlice()
+}
@@ -0,0 +1,150 @@
+// Code generated by linqgen from pipelines.go. DO NOT EDIT.
+
+package pipelines
+
+import "strings"
+
+// sumEvenSquaresGen 由 sumEvenSquares 展开生成
+func sumEvenSquaresGen(xs []int) int {
+	var result int
+	source0 := xs
+	for _, it0 := range source0 {
+		if !(it0%2 == 0) {
+			continue
+		}
+		var it2 int = it0 * it0
+		result += it2
+	}
+	return result
+}
+
+// activeNamesFast 由 activeNames 展开生成
+func activeNamesFast(users []User, offset, limit int) []string {
+	var result []string
+	source0 := users
+	skip3 := offset
+	take4 := limit
+	for _, it0 := range source0 {
+		if take4 <= 0 {
+			break
+		}
+		if !(it0.Active) {
+			continue
+		}
+		var it2 string = strings.ToUpper(it0.Name)
+		if skip3 > 0 {
+			skip3--
+			continue
+		}
+		take4--
+		result = append(result, it2)
+	}
+	return result
+}
+
+// countAdultsGen 由 countAdults 展开生成
+func countAdultsGen(users []User) int {
+	result := 0
+	source0 := users
+	for _, it0 := range source0 {
+		if !(it0.Age >= 18) {
+			continue
+		}
+		_ = it0
+		result++
+	}
+	return result
+}
+
+// anyNegativeGen 由 anyNegative 展开生成
+func anyNegativeGen(xs []float64) bool {
+	source0 := xs
+	for _, it0 := range source0 {
+		if !(it0 < 0) {
+			continue
+		}
+		_ = it0
+		return true
+	}
+	return false
+}
+
+// allShortGen 由 allShort 展开生成
+func allShortGen(words []string, n int) bool {
+	source0 := words
+	take1 := n
+	for _, it0 := range source0 {
+		if take1 <= 0 {
+			break
+		}
+		take1--
+		if !(len(it0) <= 5) {
+			return false
+		}
+	}
+	return true
+}
+
+// firstPrimesGen 由 firstPrimes 展开生成
+func firstPrimesGen(start, count, k int) []int {
+	var result []int
+	start0, count0 := start, count
+	take2 := k
+	for it0 := start0; it0 < start0+count0; it0++ {
+		if take2 <= 0 {
+			break
+		}
+		if !(isPrime(it0)) {
+			continue
+		}
+		take2--
+		result = append(result, it0)
+	}
+	return result
+}
+
+// sumScaledGen 由 sumScaled 展开生成
+func sumScaledGen(xs []float64, factor float64) float64 {
+	var result float64
+	source0 := xs
+	skip1 := 1
+	for _, it0 := range source0 {
+		if skip1 > 0 {
+			skip1--
+			continue
+		}
+		it2 := (func(x float64) float64 {
+			scaled := x * factor
+			return scaled
+		})(it0)
+		result += it2
+	}
+	return result
+}
+
+// countMultiplesGen 由 countMultiples 展开生成
+func countMultiplesGen(xs []int, k int) int {
+	result := 0
+	source0 := xs
+	fn1 := multipleOf(k)
+	for _, it0 := range source0 {
+		if !(fn1(it0)) {
+			continue
+		}
+		_ = it0
+		result++
+	}
+	return result
+}
+
+// onesGen 由 ones 展开生成
+func onesGen(xs []int) []float64 {
+	var result []float64
+	source0 := xs
+	for _, it0 := range source0 {
+		_ = it0
+		var it1 float64 = 1
+		result = append(result, it1)
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package pipelines
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func randomInts(rng *rand.Rand, n int) []int {
+	xs := make([]int, n)
+	for i := range xs {
+		xs[i] = rng.IntN(200) - 100
+	}
+	return xs
+}
+
+func randomUsers(rng *rand.Rand, n int) []User {
+	names := []string{"ann", "bob", "cy", "dee", "eve"}
+	users := make([]User, n)
+	for i := range users {
+		users[i] = User{Name: names[rng.IntN(len(names))], Age: rng.IntN(40), Active: rng.IntN(2) == 0}
+	}
+	return users
+}
+
+// TestGeneratedEquivalence 随机输入下生成代码与 Query 实现结果一致
+func TestGeneratedEquivalence(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 11))
+	for round := 0; round < 200; round++ {
+		n := rng.IntN(30)
+		xs := randomInts(rng, n)
+		fs := make([]float64, n)
+		words := make([]string, n)
+		for i, x := range xs {
+			fs[i] = float64(x) / 3
+			words[i] = string(make([]byte, rng.IntN(8)))
+		}
+		users := randomUsers(rng, n)
+		a, b := rng.IntN(10)-2, rng.IntN(10)-2
+
+		if got, want := sumEvenSquaresGen(xs), sumEvenSquares(xs); got != want {
+			t.Fatalf("sumEvenSquares(%v) 期望 %d，实际得到 %d", xs, want, got)
+		}
+		if got, want := activeNamesFast(users, a, b), activeNames(users, a, b); !slices.Equal(got, want) {
+			t.Fatalf("activeNames(%d, %d) 期望 %v，实际得到 %v", a, b, want, got)
+		}
+		if got, want := countAdultsGen(users), countAdults(users); got != want {
+			t.Fatalf("countAdults 期望 %d，实际得到 %d", want, got)
+		}
+		if got, want := anyNegativeGen(fs), anyNegative(fs); got != want {
+			t.Fatalf("anyNegative(%v) 期望 %v，实际得到 %v", fs, want, got)
+		}
+		if got, want := allShortGen(words, a), allShort(words, a); got != want {
+			t.Fatalf("allShort(%d) 期望 %v，实际得到 %v", a, want, got)
+		}
+		if got, want := firstPrimesGen(a, n*5, b), firstPrimes(a, n*5, b); !slices.Equal(got, want) {
+			t.Fatalf("firstPrimes(%d, %d, %d) 期望 %v，实际得到 %v", a, n*5, b, want, got)
+		}
+		if got, want := sumScaledGen(fs, 1.5), sumScaled(fs, 1.5); got != want {
+			t.Fatalf("sumScaled 期望 %v，实际得到 %v", want, got)
+		}
+		k := rng.IntN(5) + 1
+		multipleOfCalls = 0
+		want := countMultiples(xs, k)
+		wantCalls := multipleOfCalls
+		multipleOfCalls = 0
+		if got := countMultiplesGen(xs, k); got != want || multipleOfCalls != wantCalls {
+			t.Fatalf("countMultiples(%d) 期望 %d（工厂调用 %d 次），实际得到 %d（%d 次）", k, want, wantCalls, got, multipleOfCalls)
+		}
+		if got, want := onesGen(xs), ones(xs); !slices.Equal(got, want) {
+			t.Fatalf("ones 期望 %v，实际得到 %v", want, got)
+		}
+	}
+}
+
+var benchData = func() []int {
+	return randomInts(rand.New(rand.NewPCG(1, 2)), 10000)
+}()
+
+var benchUsers = func() []User {
+	return randomUsers(rand.New(rand.NewPCG(3, 4)), 10000)
+}()
+
+// BenchmarkSumEvenSquares 基准测试：Query 管道
+func BenchmarkSumEvenSquares(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		sumEvenSquares(benchData)
+	}
+}
+
+// BenchmarkSumEvenSquaresGen 基准测试：生成代码
+func BenchmarkSumEvenSquaresGen(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		sumEvenSquaresGen(benchData)
+	}
+}
+
+// BenchmarkActiveNames 基准测试：Query 管道
+func BenchmarkActiveNames(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		activeNames(benchUsers, 100, 1000)
+	}
+}
+
+// BenchmarkActiveNamesGen 基准测试：生成代码
+func BenchmarkActiveNamesGen(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		activeNamesFast(benchUsers, 100, 1000)
+	}
+}
+
+// BenchmarkCountAdults 基准测试：Query 管道
+func BenchmarkCountAdults(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		countAdults(benchUsers)
+	}
+}
+
+// BenchmarkCountAdultsGen 基准测试：生成代码
+func BenchmarkCountAdultsGen(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		countAdultsGen(benchUsers)
+	}
+}
@@ -0,0 +1,523 @@
+// linqgen 将标注了 //linq:generate 的 Query 管道函数展开为等价的手写循环，消除每个元素的间接调用。
+//
+// 被标注的函数体须为单个 return 语句，返回一条以 From(slice) 或 QueryRange(start, count) 开头、
+// 以终结操作结尾的管道：
+//
+//	//linq:generate
+//	func sumEvenSquares(xs []int) int {
+//		return linq.Sum(linq.Select(linq.From(xs).Where(func(x int) bool { return x%2 == 0 }),
+//			func(x int) int { return x * x }))
+//	}
+//
+// 支持的中间操作：Where、Skip、Take、Select；终结操作：ToSlice、Count、Any、All、Sum。
+// 单表达式函数字面量直接内联，其余函数值在循环前求值一次后调用。生成的函数默认命名为原函数名加 Gen 后缀，
+// 也可通过 //linq:generate 名称 指定，输出到同目录下的 <文件名>_linqgen.go；生成的局部变量与函数中已有的标识符同名时自动追加后缀。
+//
+// 用法：
+//
+//	//go:generate go run github.com/livexy/linq/cmd/linqgen $GOFILE
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	linqImportPath = "github.com/livexy/linq"
+	directive      = "//linq:generate"
+)
+
+func main() {
+	output := flag.String("o", "", "输出文件（仅处理单个输入文件时可用），默认为 <文件名>_linqgen.go")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "用法: linqgen [-o output.go] file.go...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || (*output != "" && flag.NArg() > 1) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	for _, path := range flag.Args() {
+		src, err := generateFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "linqgen:", err)
+			os.Exit(1)
+		}
+		out := *output
+		if out == "" {
+			out = outputPath(path)
+		}
+		if err := os.WriteFile(out, src, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, "linqgen:", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// outputPath 返回输入文件对应的生成文件路径
+func outputPath(path string) string {
+	return strings.TrimSuffix(path, ".go") + "_linqgen.go"
+}
+
+// generateFile 解析文件并生成全部标注函数的展开代码
+func generateFile(path string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	linqName := importName(file, linqImportPath)
+	if linqName == "" {
+		return nil, fmt.Errorf("%s: 未导入 %s", path, linqImportPath)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by linqgen from %s. DO NOT EDIT.\n\n", filepath.Base(path))
+	fmt.Fprintf(&buf, "package %s\n\n", file.Name.Name)
+	imports := buf.Len()
+	count := 0
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		name, ok := generateName(fd)
+		if !ok {
+			continue
+		}
+		g := &generator{fset: fset, linq: linqName}
+		code, err := g.function(fd, name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", fset.Position(fd.Pos()), fd.Name.Name, err)
+		}
+		buf.WriteString(code)
+		count++
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%s: 没有 %s 标注的函数", path, directive)
+	}
+
+	body := buf.Bytes()
+	used := usedPackages(body[imports:])
+	var importBlock strings.Builder
+	for _, spec := range file.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		name := packageName(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if !used[name] {
+			continue
+		}
+		if spec.Name != nil {
+			fmt.Fprintf(&importBlock, "import %s %s\n", spec.Name.Name, spec.Path.Value)
+		} else {
+			fmt.Fprintf(&importBlock, "import %s\n", spec.Path.Value)
+		}
+	}
+	src := append(append(append([]byte(nil), body[:imports]...), importBlock.String()+"\n"...), body[imports:]...)
+	return format.Source(src)
+}
+
+// importName 返回文件中导入 path 使用的包名
+func importName(file *ast.File, path string) string {
+	for _, spec := range file.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p == path {
+			if spec.Name != nil {
+				return spec.Name.Name
+			}
+			return packageName(path)
+		}
+	}
+	return ""
+}
+
+// packageName 按惯例由导入路径推断包名，跳过末尾的版本号（如 math/rand/v2）
+func packageName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = parts[len(parts)-2]
+	}
+	return name
+}
+
+// generateName 读取函数的 //linq:generate 标注，返回生成函数的名称
+func generateName(fd *ast.FuncDecl) (string, bool) {
+	if fd.Doc == nil {
+		return "", false
+	}
+	for _, c := range fd.Doc.List {
+		rest, ok := strings.CutPrefix(c.Text, directive)
+		if !ok || rest != "" && rest[0] != ' ' {
+			continue
+		}
+		if name := strings.TrimSpace(rest); name != "" {
+			return name, true
+		}
+		return fd.Name.Name + "Gen", true
+	}
+	return "", false
+}
+
+// usedPackages 收集生成代码中作为选择器前缀出现的标识符
+func usedPackages(src []byte) map[string]bool {
+	used := make(map[string]bool)
+	file, err := parser.ParseFile(token.NewFileSet(), "", append([]byte("package p\n"), src...), 0)
+	if err != nil {
+		return used
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+	return used
+}
+
+// stage 管道中的一个操作
+type stage struct {
+	op   string     // From、QueryRange、Where、Skip、Take、Select 或终结操作名
+	args []ast.Expr // 不含上游查询的参数
+}
+
+type generator struct {
+	fset *token.FileSet
+	linq string
+}
+
+var (
+	errShape       = errors.New("函数体须为单个 return 语句")
+	errUnsupported = errors.New("不支持的管道操作")
+)
+
+// function 生成单个函数
+func (g *generator) function(fd *ast.FuncDecl, name string) (string, error) {
+	if fd.Body == nil || len(fd.Body.List) != 1 {
+		return "", errShape
+	}
+	ret, ok := fd.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", errShape
+	}
+	stages, err := g.pipeline(ret.Results[0])
+	if err != nil {
+		return "", err
+	}
+	if len(stages) < 2 || !isTerminal(stages[len(stages)-1].op) {
+		return "", fmt.Errorf("%w: 管道须以终结操作结尾", errUnsupported)
+	}
+	terminal := stages[len(stages)-1]
+	results := fd.Type.Results
+	if terminal.op == "ToSlice" || terminal.op == "Sum" {
+		if results == nil || len(results.List) != 1 {
+			return "", fmt.Errorf("%w: %s 需要声明单个返回值", errShape, terminal.op)
+		}
+	}
+
+	// 生成的局部变量避开函数中已出现的标识符（参数、返回值、被捕获的变量等）
+	v := newNamer(fd)
+	result := v.name("result")
+	header := &ast.FuncDecl{Name: ast.NewIdent(name), Type: fd.Type}
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s 由 %s 展开生成\n", name, fd.Name.Name)
+	b.WriteString(g.node(header))
+	b.WriteString(" {\n")
+
+	switch terminal.op {
+	case "ToSlice", "Sum":
+		fmt.Fprintf(&b, "var %s %s\n", result, g.node(results.List[0].Type))
+	case "Count":
+		fmt.Fprintf(&b, "%s := 0\n", result)
+	}
+
+	// 源及计数参数按管道顺序在循环前求值，与构造 Query 时一致
+	src := stages[0]
+	source, start, count := v.name("source0"), v.name("start0"), v.name("count0")
+	switch src.op {
+	case "From":
+		fmt.Fprintf(&b, "%s := %s\n", source, g.node(src.args[0]))
+	case "QueryRange":
+		fmt.Fprintf(&b, "%s, %s := %s, %s\n", start, count, g.node(src.args[0]), g.node(src.args[1]))
+	}
+	var takes []string
+	fns := make(map[int]ast.Expr)
+	for i, s := range stages {
+		switch s.op {
+		case "Where", "Select", "All":
+			if !hoisted(s.args[0]) {
+				break
+			}
+			fn := v.name(fmt.Sprintf("fn%d", i))
+			fmt.Fprintf(&b, "%s := %s\n", fn, g.node(s.args[0]))
+			fns[i] = ast.NewIdent(fn)
+		case "Skip":
+			fmt.Fprintf(&b, "%s := %s\n", v.name(fmt.Sprintf("skip%d", i)), g.node(s.args[0]))
+		case "Take":
+			take := v.name(fmt.Sprintf("take%d", i))
+			fmt.Fprintf(&b, "%s := %s\n", take, g.node(s.args[0]))
+			takes = append(takes, take+" <= 0")
+		}
+	}
+
+	cur := v.name("it0")
+	switch src.op {
+	case "From":
+		fmt.Fprintf(&b, "for _, %s := range %s {\n", cur, source)
+	case "QueryRange":
+		fmt.Fprintf(&b, "for %[1]s := %[2]s; %[1]s < %[2]s+%[3]s; %[1]s++ {\n", cur, start, count)
+	}
+	if len(takes) > 0 {
+		fmt.Fprintf(&b, "if %s {\nbreak\n}\n", strings.Join(takes, " || "))
+	}
+
+	fnArg := func(i int) ast.Expr {
+		if fn, ok := fns[i]; ok {
+			return fn
+		}
+		return stages[i].args[0]
+	}
+	for i, s := range stages[1 : len(stages)-1] {
+		i++
+		switch s.op {
+		case "Where":
+			discard(&b, fnArg(i), cur)
+			fmt.Fprintf(&b, "if !(%s) {\ncontinue\n}\n", g.apply(fnArg(i), cur))
+		case "Skip":
+			skip := v.name(fmt.Sprintf("skip%d", i))
+			fmt.Fprintf(&b, "if %[1]s > 0 {\n%[1]s--\ncontinue\n}\n", skip)
+		case "Take":
+			fmt.Fprintf(&b, "%s--\n", v.name(fmt.Sprintf("take%d", i)))
+		case "Select":
+			next := v.name(fmt.Sprintf("it%d", i))
+			discard(&b, fnArg(i), cur)
+			// 内联后的表达式可能是无类型常量，按函数字面量的返回类型声明
+			if typ := inlinedType(s.args[0]); typ != nil {
+				fmt.Fprintf(&b, "var %s %s = %s\n", next, g.node(typ), g.apply(fnArg(i), cur))
+			} else {
+				fmt.Fprintf(&b, "%s := %s\n", next, g.apply(fnArg(i), cur))
+			}
+			cur = next
+		}
+	}
+
+	switch terminal.op {
+	case "ToSlice":
+		fmt.Fprintf(&b, "%[1]s = append(%[1]s, %[2]s)\n}\nreturn %[1]s\n", result, cur)
+	case "Sum":
+		fmt.Fprintf(&b, "%[1]s += %[2]s\n}\nreturn %[1]s\n", result, cur)
+	case "Count":
+		fmt.Fprintf(&b, "_ = %[2]s\n%[1]s++\n}\nreturn %[1]s\n", result, cur)
+	case "Any":
+		fmt.Fprintf(&b, "_ = %s\nreturn true\n}\nreturn false\n", cur)
+	case "All":
+		discard(&b, fnArg(len(stages)-1), cur)
+		fmt.Fprintf(&b, "if !(%s) {\nreturn false\n}\n}\nreturn true\n", g.apply(fnArg(len(stages)-1), cur))
+	}
+	b.WriteString("}\n\n")
+	return b.String(), nil
+}
+
+// namer 为生成的局部变量选取不与函数中已有标识符冲突的名称
+type namer struct {
+	used  map[string]bool
+	names map[string]string
+}
+
+func newNamer(fd *ast.FuncDecl) *namer {
+	used := make(map[string]bool)
+	ast.Inspect(fd, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			used[id.Name] = true
+		}
+		return true
+	})
+	return &namer{used: used, names: make(map[string]string)}
+}
+
+// name 返回 base 对应的名称，同一 base 总是得到同一名称；冲突时追加 _1、_2 等后缀
+func (v *namer) name(base string) string {
+	if n, ok := v.names[base]; ok {
+		return n
+	}
+	n := base
+	for i := 1; v.used[n]; i++ {
+		n = base + "_" + strconv.Itoa(i)
+	}
+	v.used[n] = true
+	v.names[base] = n
+	return n
+}
+
+// pipeline 将嵌套的调用表达式展开为从源到终结操作的操作序列
+func (g *generator) pipeline(expr ast.Expr) ([]stage, error) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnsupported, g.node(expr))
+	}
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr: // 显式实例化 linq.Select[T, V]
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnsupported, g.node(call.Fun))
+	}
+	op := sel.Sel.Name
+
+	// 包级函数：linq.From / linq.QueryRange / linq.Select / linq.Sum
+	if id, ok := sel.X.(*ast.Ident); ok && id.Name == g.linq {
+		switch op {
+		case "From":
+			if len(call.Args) != 1 {
+				break
+			}
+			return []stage{{op: op, args: call.Args}}, nil
+		case "QueryRange":
+			if len(call.Args) != 2 {
+				break
+			}
+			return []stage{{op: op, args: call.Args}}, nil
+		case "Select", "Sum":
+			want := map[string]int{"Select": 2, "Sum": 1}[op]
+			if len(call.Args) != want {
+				break
+			}
+			upstream, err := g.pipeline(call.Args[0])
+			if err != nil {
+				return nil, err
+			}
+			return append(upstream, stage{op: op, args: call.Args[1:]}), nil
+		}
+		return nil, fmt.Errorf("%w: %s.%s", errUnsupported, g.linq, op)
+	}
+
+	// 方法调用
+	arity := map[string]int{"Where": 1, "Skip": 1, "Take": 1, "ToSlice": 0, "Count": 0, "Any": 0, "All": 1}
+	want, ok := arity[op]
+	if !ok || len(call.Args) != want {
+		return nil, fmt.Errorf("%w: .%s", errUnsupported, op)
+	}
+	upstream, err := g.pipeline(sel.X)
+	if err != nil {
+		return nil, err
+	}
+	if isTerminal(upstream[len(upstream)-1].op) {
+		return nil, fmt.Errorf("%w: 终结操作之后不能再接 .%s", errUnsupported, op)
+	}
+	return append(upstream, stage{op: op, args: call.Args}), nil
+}
+
+func isTerminal(op string) bool {
+	switch op {
+	case "ToSlice", "Count", "Any", "All", "Sum":
+		return true
+	}
+	return false
+}
+
+// apply 生成以 arg 调用函数 fn 的表达式：单表达式函数字面量内联并将参数替换为 arg，否则直接调用；
+// 参数名为 _ 时 obj 为 nil，表达式原样保留
+func (g *generator) apply(fn ast.Expr, arg string) string {
+	if lit, result := inlinable(fn); lit != nil {
+		param := lit.Type.Params.List[0].Names[0]
+		return g.node(renameIdent(result, param.Obj, arg))
+	}
+	if _, ok := ast.Unparen(fn).(*ast.FuncLit); ok {
+		return fmt.Sprintf("(%s)(%s)", g.node(fn), arg)
+	}
+	return fmt.Sprintf("%s(%s)", g.node(fn), arg)
+}
+
+// inlinable 返回可内联的单参数、单表达式函数字面量及其返回的表达式，不可内联时返回 nil
+func inlinable(fn ast.Expr) (*ast.FuncLit, ast.Expr) {
+	lit, ok := ast.Unparen(fn).(*ast.FuncLit)
+	if !ok || len(lit.Type.Params.List) != 1 || len(lit.Type.Params.List[0].Names) != 1 || len(lit.Body.List) != 1 {
+		return nil, nil
+	}
+	if ret, ok := lit.Body.List[0].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+		return lit, ret.Results[0]
+	}
+	return nil, nil
+}
+
+// hoisted 报告函数值是否需要在循环前求值一次：函数字面量与函数名不必，
+// 其余表达式（如 multipleOf(3)、变量）与构造 Query 时一样只求值一次
+func hoisted(fn ast.Expr) bool {
+	switch f := ast.Unparen(fn).(type) {
+	case *ast.FuncLit:
+		return false
+	case *ast.Ident:
+		return f.Obj != nil && f.Obj.Kind != ast.Fun
+	}
+	return true
+}
+
+// discard 在内联的函数字面量不使用参数时写入 _ = arg，避免循环变量未使用
+func discard(b *strings.Builder, fn ast.Expr, arg string) {
+	lit, result := inlinable(fn)
+	if lit == nil {
+		return
+	}
+	obj := lit.Type.Params.List[0].Names[0].Obj
+	used := false
+	ast.Inspect(result, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && obj != nil && id.Obj == obj {
+			used = true
+		}
+		return !used
+	})
+	if !used {
+		fmt.Fprintf(b, "_ = %s\n", arg)
+	}
+}
+
+// inlinedType 返回内联后表达式应有的类型，即函数字面量的返回值类型；不内联时返回 nil
+func inlinedType(fn ast.Expr) ast.Expr {
+	lit, _ := inlinable(fn)
+	if lit == nil || lit.Type.Results == nil || len(lit.Type.Results.List) != 1 || len(lit.Type.Results.List[0].Names) > 1 {
+		return nil
+	}
+	return lit.Type.Results.List[0].Type
+}
+
+// renameIdent 将引用 obj 的标识符重命名为 name（就地修改，每个函数字面量只展开一次）
+func renameIdent(expr ast.Expr, obj *ast.Object, name string) ast.Expr {
+	if obj == nil {
+		return expr
+	}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Obj == obj {
+			id.Name = name
+		}
+		return true
+	})
+	return expr
+}
+
+func (g *generator) node(n ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, g.fset, n); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
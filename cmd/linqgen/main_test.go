@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGeneratedUpToDate 确认示例包中提交的生成文件与当前生成器输出一致
+func TestGeneratedUpToDate(t *testing.T) {
+	input := filepath.Join("internal", "pipelines", "pipelines.go")
+	got, err := generateFile(input)
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	want, err := os.ReadFile(outputPath(input))
+	if err != nil {
+		t.Fatalf("读取生成文件失败: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s 已过期，请执行 go generate ./cmd/linqgen/...", outputPath(input))
+	}
+}
+
+func writeSource(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "src.go")
+	src := "package p\n\nimport (\n\tq \"github.com/livexy/linq\"\n\t\"strconv\"\n\t\"strings\"\n)\n\nvar _ = strings.ToUpper\n\n" + body
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestGenerateFile 测试命名、包别名与导入裁剪
+func TestGenerateFile(t *testing.T) {
+	path := writeSource(t, `
+//linq:generate
+func a(xs []int) []string {
+	return q.Select(q.From(xs).Where(func(_ int) bool { return true }), func(x int) string { return strconv.Itoa(x) }).ToSlice()
+}
+
+// 说明文字
+//linq:generate fastB
+func b(xs []int) int {
+	return q.From(xs).Count()
+}
+
+func plain(xs []int) int { return q.From(xs).Count() }
+`)
+	out, err := generateFile(path)
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	src := string(out)
+	for _, want := range []string{"func aGen(xs []int) []string", "func fastB(xs []int) int", `import "strconv"`, "strconv.Itoa(it0)"} {
+		if !strings.Contains(src, want) {
+			t.Errorf("生成结果应包含 %q:\n%s", want, src)
+		}
+	}
+	for _, unwanted := range []string{"plainGen", `"strings"`, "github.com/livexy/linq", "func(x int)"} {
+		if strings.Contains(src, unwanted) {
+			t.Errorf("生成结果不应包含 %q:\n%s", unwanted, src)
+		}
+	}
+}
+
+// TestGenerateNameCollisions 生成的局部变量与参数、被捕获的变量同名时自动改名，结果可通过类型检查
+func TestGenerateNameCollisions(t *testing.T) {
+	path := writeSource(t, `
+var it1, take2 = 1, 2
+
+//linq:generate
+func count(result []int) int {
+	return q.From(result).Count()
+}
+
+//linq:generate
+func sum(source0 []int, skip1 int) (result int) {
+	return q.Sum(q.Select(q.From(source0).Skip(skip1).Take(take2), func(x int) int { return x + it1 }))
+}
+
+//linq:generate
+func rng(start0, count0, it0 int) bool {
+	return q.QueryRange(start0, count0).All(func(x int) bool { return x != it0 })
+}
+`)
+	out, err := generateFile(path)
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	// 补充原文件中的包级变量后做类型检查
+	src := string(out) + "\nvar it1, take2 = 1, 2\n"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "gen.go", src, 0)
+	if err != nil {
+		t.Fatalf("解析生成结果失败: %v\n%s", err, src)
+	}
+	if _, err := new(types.Config).Check("p", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("生成结果应可通过类型检查: %v\n%s", err, src)
+	}
+	for _, want := range []string{"result_1 := 0", "source0_1 := source0", "take2_1 := take2", "it0_1 != it0"} {
+		if !strings.Contains(src, want) {
+			t.Errorf("生成结果应包含 %q:\n%s", want, src)
+		}
+	}
+}
+
+// TestGenerateFuncValues 非字面量函数值在循环前求值一次，内联的 Select 按返回类型声明变量
+func TestGenerateFuncValues(t *testing.T) {
+	path := writeSource(t, `
+func multipleOf(k int) func(int) bool { return func(x int) bool { return x%k == 0 } }
+
+//linq:generate
+func f(xs []int) []float64 {
+	return q.Select(q.From(xs).Where(multipleOf(3)), func(x int) float64 { return 1 }).ToSlice()
+}
+`)
+	out, err := generateFile(path)
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	src := string(out) + "\nfunc multipleOf(k int) func(int) bool { return func(x int) bool { return x%k == 0 } }\n"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "gen.go", src, 0)
+	if err != nil {
+		t.Fatalf("解析生成结果失败: %v\n%s", err, src)
+	}
+	if _, err := new(types.Config).Check("p", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("生成结果应可通过类型检查: %v\n%s", err, src)
+	}
+	for _, want := range []string{"fn1 := multipleOf(3)\n\tfor", "!(fn1(it0))", "var it2 float64 = 1"} {
+		if !strings.Contains(src, want) {
+			t.Errorf("生成结果应包含 %q:\n%s", want, src)
+		}
+	}
+}
+
+// TestGenerateErrors 测试不支持的写法返回错误
+func TestGenerateErrors(t *testing.T) {
+	cases := map[string]struct {
+		body string
+		err  error
+	}{
+		"多条语句": {`
+//linq:generate
+func f(xs []int) int {
+	n := 0
+	return n
+}`, errShape},
+		"不支持的操作": {`
+//linq:generate
+func f(xs []int) []int {
+	return q.From(xs).Reverse().ToSlice()
+}`, errUnsupported},
+		"缺少终结操作": {`
+//linq:generate
+func f(xs []int) q.Query[int] {
+	return q.From(xs).Where(func(x int) bool { return x > 0 })
+}`, errUnsupported},
+		"不支持的源": {`
+//linq:generate
+func f(ch chan int) int {
+	return q.FromChannel(ch).Count()
+}`, errUnsupported},
+	}
+	for name, c := range cases {
+		if _, err := generateFile(writeSource(t, c.body)); !errors.Is(err, c.err) {
+			t.Errorf("%s: 期望 %v，实际得到 %v", name, c.err, err)
+		}
+	}
+	if _, err := generateFile(writeSource(t, "func f() {}\n")); err == nil {
+		t.Errorf("没有标注函数时应返回错误")
+	}
+}
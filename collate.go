@@ -0,0 +1,311 @@
+package linq
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Collator 字符串排序规则，Compare 返回负数、0、正数分别表示 a 小于、等于、大于 b
+type Collator interface {
+	Compare(a, b string) int
+}
+
+// CollatorFunc 将比较函数适配为 Collator
+type CollatorFunc func(a, b string) int
+
+// Compare 实现 Collator
+func (f CollatorFunc) Compare(a, b string) int {
+	return f(a, b)
+}
+
+// AscCollate 根据键选择器及排序规则生成升序比较器
+func AscCollate[T comparable](c Collator, selector func(T) string) CompareFunc[T] {
+	return func(a, b T) int {
+		return c.Compare(selector(a), selector(b))
+	}
+}
+
+// DescCollate 根据键选择器及排序规则生成降序比较器
+func DescCollate[T comparable](c Collator, selector func(T) string) CompareFunc[T] {
+	return func(a, b T) int {
+		return c.Compare(selector(b), selector(a))
+	}
+}
+
+// AscNatural 自然排序升序比较器，字符串中的数字按数值比较（"file2" 排在 "file10" 之前）
+func AscNatural[T comparable](selector func(T) string) CompareFunc[T] {
+	return AscCollate(CollatorFunc(CompareNatural), selector)
+}
+
+// DescNatural 自然排序降序比较器
+func DescNatural[T comparable](selector func(T) string) CompareFunc[T] {
+	return DescCollate(CollatorFunc(CompareNatural), selector)
+}
+
+// AscFold 忽略大小写（Unicode 大小写折叠）的升序比较器
+func AscFold[T comparable](selector func(T) string) CompareFunc[T] {
+	return AscCollate(CollatorFunc(CompareFold), selector)
+}
+
+// DescFold 忽略大小写（Unicode 大小写折叠）的降序比较器
+func DescFold[T comparable](selector func(T) string) CompareFunc[T] {
+	return DescCollate(CollatorFunc(CompareFold), selector)
+}
+
+// CompareNatural 自然顺序比较：连续的 ASCII 数字按数值比较，其余字符按码点比较；
+// 仅前导零不同时（如 "a01" 与 "a1"）按字节序决定
+func CompareNatural(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			si, sj := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			if r := compareDigits(a[si:i], b[sj:j]); r != 0 {
+				return r
+			}
+			continue
+		}
+		ra, wa := utf8.DecodeRuneInString(a[i:])
+		rb, wb := utf8.DecodeRuneInString(b[j:])
+		if ra != rb {
+			if ra < rb {
+				return -1
+			}
+			return 1
+		}
+		i += wa
+		j += wb
+	}
+	switch {
+	case len(a)-i < len(b)-j:
+		return -1
+	case len(a)-i > len(b)-j:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// compareDigits 按数值比较两段数字，不受长度限制
+func compareDigits(a, b string) int {
+	ta, tb := strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(ta) != len(tb) {
+		if len(ta) < len(tb) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(ta, tb)
+}
+
+// CompareFold 忽略大小写比较，大小写折叠后相同的字符串视为相等
+func CompareFold(a, b string) int {
+	for a != "" && b != "" {
+		ra, wa := utf8.DecodeRuneInString(a)
+		rb, wb := utf8.DecodeRuneInString(b)
+		if fa, fb := foldRune(ra), foldRune(rb); fa != fb {
+			if fa < fb {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[wa:], b[wb:]
+	}
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+	return 1
+}
+
+// foldRune 返回字符所在大小写折叠等价类的统一小写形式
+func foldRune(r rune) rune {
+	m := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < m {
+			m = f
+		}
+	}
+	return unicode.ToLower(m)
+}
+
+// UnicodeCollator 返回内置的多级排序规则：
+//   - 第一级按基本字符：忽略大小写与拉丁字母变音符号（é 与 e 相同，ß 视为 ss），空白与标点在前、数字其次、字母最后；
+//     汉字按码点即部首笔画顺序
+//   - 第二级区分变音符号，第三级小写在前，仍相同时按码点决定
+func UnicodeCollator() Collator {
+	return tableCollator{}
+}
+
+// NewTableCollator 基于字符排序键表创建排序规则，用于拼音等需要外部数据的排序：
+// 表中的字符以其排序键（如 "zhang"）参与第一级比较，与拉丁字母混排；排序键相同时按码点区分，
+// 表外字符按 UnicodeCollator 规则处理
+func NewTableCollator(table map[rune]string) Collator {
+	return tableCollator{table: table}
+}
+
+type tableCollator struct {
+	table map[rune]string
+}
+
+// Compare 实现 Collator，逐级边遍历边比较，不生成中间排序键
+func (c tableCollator) Compare(a, b string) int {
+	pa, pb := primaryIter{c: c, s: a}, primaryIter{c: c, s: b}
+	for {
+		wa, oka := pa.next()
+		wb, okb := pb.next()
+		if !oka || !okb {
+			if oka != okb {
+				if oka {
+					return 1
+				}
+				return -1
+			}
+			break
+		}
+		if wa != wb {
+			if wa < wb {
+				return -1
+			}
+			return 1
+		}
+	}
+	if r := compareLevel(a, b, c.accent); r != 0 {
+		return r
+	}
+	if r := compareLevel(a, b, caseWeight); r != 0 {
+		return r
+	}
+	return strings.Compare(a, b)
+}
+
+// base 返回字符的基本字符串，无映射时为空
+func (c tableCollator) base(r rune) string {
+	if mapped, ok := c.table[r]; ok {
+		return mapped
+	}
+	return latinBase[unicode.ToLower(r)]
+}
+
+// accent 第二级权重：拉丁变音字母为其小写形式，其余为 0
+func (c tableCollator) accent(r rune) rune {
+	if _, ok := c.table[r]; ok {
+		return 0
+	}
+	if lower := unicode.ToLower(r); latinBase[lower] != "" {
+		return lower
+	}
+	return 0
+}
+
+// caseWeight 第三级权重：大写为 1
+func caseWeight(r rune) rune {
+	if unicode.IsUpper(r) {
+		return 1
+	}
+	return 0
+}
+
+// primaryIter 逐个产出第一级权重，基本字符串展开为多个权重
+type primaryIter struct {
+	c    tableCollator
+	s    string
+	base string
+}
+
+func (it *primaryIter) next() (rune, bool) {
+	if it.base == "" {
+		if it.s == "" {
+			return 0, false
+		}
+		r, w := utf8.DecodeRuneInString(it.s)
+		it.s = it.s[w:]
+		if it.base = it.c.base(r); it.base == "" {
+			return primaryWeight(foldRune(r)), true
+		}
+	}
+	r, w := utf8.DecodeRuneInString(it.base)
+	it.base = it.base[w:]
+	return primaryWeight(foldRune(r)), true
+}
+
+// compareLevel 按每个字符的权重逐个比较，前缀相同时较短者在前
+func compareLevel(a, b string, weight func(rune) rune) int {
+	for a != "" && b != "" {
+		ra, wa := utf8.DecodeRuneInString(a)
+		rb, wb := utf8.DecodeRuneInString(b)
+		if xa, xb := weight(ra), weight(rb); xa != xb {
+			if xa < xb {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[wa:], b[wb:]
+	}
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+	return 1
+}
+
+// primaryWeight 在码点之上加入字符类别，使空白标点 < 数字 < 字母
+func primaryWeight(r rune) rune {
+	const shift = 21 // unicode.MaxRune < 1<<21
+	switch {
+	case unicode.IsLetter(r):
+		return 2<<shift | r
+	case unicode.IsDigit(r):
+		return 1<<shift | r
+	}
+	return r
+}
+
+// latinBase 拉丁字母变音符号及连字到基本字母的映射（键为小写）
+var latinBase = func() map[rune]string {
+	groups := map[string]string{
+		"a":  "àáâãäåāăąǎ",
+		"c":  "çćĉċč",
+		"d":  "ďđ",
+		"e":  "èéêëēĕėęě",
+		"g":  "ĝğġģ",
+		"h":  "ĥħ",
+		"i":  "ìíîïĩīĭįıǐ",
+		"j":  "ĵ",
+		"k":  "ķ",
+		"l":  "ĺļľŀł",
+		"n":  "ñńņň",
+		"o":  "òóôõöøōŏőǒ",
+		"r":  "ŕŗř",
+		"s":  "śŝşš",
+		"t":  "ţťŧ",
+		"u":  "ùúûüũūŭůűųǔǖǘǚǜ",
+		"w":  "ŵ",
+		"y":  "ýÿŷ",
+		"z":  "źżž",
+		"ss": "ß",
+		"ae": "æ",
+		"oe": "œ",
+		"th": "þ",
+	}
+	table := make(map[rune]string, 160)
+	for base, chars := range groups {
+		for _, r := range chars {
+			table[r] = base
+		}
+	}
+	return table
+}()
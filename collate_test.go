@@ -0,0 +1,92 @@
+package linq
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestCompareNatural 测试自然排序
+func TestCompareNatural(t *testing.T) {
+	files := []string{"file10.txt", "file2.txt", "file1.txt", "File3.txt", "file02.txt", "file", "file100a", "file100"}
+	got := From(files).Order(AscNatural(func(s string) string { return s })).ToSlice()
+	want := []string{"File3.txt", "file", "file1.txt", "file02.txt", "file2.txt", "file10.txt", "file100", "file100a"}
+	if !slices.Equal(got, want) {
+		t.Errorf("期望 %v，实际得到 %v", want, got)
+	}
+	if CompareNatural("v1.10.0", "v1.9.3") <= 0 {
+		t.Errorf("版本号 v1.10.0 应大于 v1.9.3")
+	}
+	if CompareNatural("a99999999999999999999999", "a100000000000000000000000") >= 0 {
+		t.Errorf("超长数字应按数值比较")
+	}
+	if CompareNatural("a01", "a1") == 0 || CompareNatural("x", "x") != 0 {
+		t.Errorf("仅前导零不同时不应相等")
+	}
+	desc := From([]string{"a2", "a10", "a1"}).Order(DescNatural(func(s string) string { return s })).ToSlice()
+	if !slices.Equal(desc, []string{"a10", "a2", "a1"}) {
+		t.Errorf("DescNatural 错误: %v", desc)
+	}
+}
+
+// TestCompareFold 测试忽略大小写排序
+func TestCompareFold(t *testing.T) {
+	if CompareFold("Hello", "hELLO") != 0 || CompareFold("ΣΑΣ", "σας") != 0 {
+		t.Errorf("大小写折叠后应相等")
+	}
+	if CompareFold("apple", "Banana") >= 0 || CompareFold("ab", "A") <= 0 {
+		t.Errorf("CompareFold 顺序错误")
+	}
+	got := From([]string{"b", "B", "a", "C", "A"}).Order(AscFold(func(s string) string { return s })).ToSlice()
+	if !slices.Equal(got, []string{"a", "A", "b", "B", "C"}) {
+		t.Errorf("AscFold 应稳定且忽略大小写: %v", got)
+	}
+	if got := From([]string{"a", "B"}).Order(DescFold(func(s string) string { return s })).First(); got != "B" {
+		t.Errorf("DescFold 期望 B，实际得到 %s", got)
+	}
+}
+
+// TestUnicodeCollator 测试内置多级排序规则
+func TestUnicodeCollator(t *testing.T) {
+	c := UnicodeCollator()
+	words := []string{"zebra", "Éclair", "eclair", "écrit", "Eclair", "ecru", "Straße", "strasse", "10", "_x", "apple"}
+	got := From(words).Order(AscCollate(c, func(s string) string { return s })).ToSlice()
+	want := []string{"_x", "10", "apple", "eclair", "Eclair", "Éclair", "écrit", "ecru", "strasse", "Straße", "zebra"}
+	if !slices.Equal(got, want) {
+		t.Errorf("期望 %v，实际得到 %v", want, got)
+	}
+	if c.Compare("a", "a") != 0 {
+		t.Errorf("相同字符串应相等")
+	}
+}
+
+// TestTableCollator 测试排序键表（拼音）
+func TestTableCollator(t *testing.T) {
+	pinyin := map[rune]string{
+		'张': "zhang", '章': "zhang", '李': "li", '王': "wang", '赵': "zhao", '阿': "a",
+	}
+	c := NewTableCollator(pinyin)
+	type person struct{ Name string }
+	people := From([]person{{"张三"}, {"李四"}, {"王五"}, {"赵六"}, {"阿七"}, {"Lucy"}, {"章八"}})
+	got := Select(people.Order(AscCollate(c, func(p person) string { return p.Name })).Query,
+		func(p person) string { return p.Name }).ToSlice()
+	want := []string{"阿七", "李四", "Lucy", "王五", "张三", "章八", "赵六"}
+	if !slices.Equal(got, want) {
+		t.Errorf("期望 %v，实际得到 %v", want, got)
+	}
+	desc := From([]string{"李", "张"}).Order(DescCollate(c, func(s string) string { return s })).First()
+	if desc != "张" {
+		t.Errorf("DescCollate 期望 张，实际得到 %s", desc)
+	}
+}
+
+// TestTableCollatorAllocs 比较过程不应分配内存
+func TestTableCollatorAllocs(t *testing.T) {
+	c := NewTableCollator(map[rune]string{'张': "zhang"})
+	allocs := testing.AllocsPerRun(100, func() {
+		c.Compare("Straße 张三", "strasse 张四")
+		c.Compare("Éclair", "eclair")
+	})
+	if allocs != 0 {
+		t.Errorf("期望比较时无内存分配，实际得到 %v 次", allocs)
+	}
+}
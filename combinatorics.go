@@ -0,0 +1,222 @@
+package linq
+
+// 组合数学相关操作均为惰性求值：仅缓存输入元素，按需逐个生成结果，配合 Take 可提前终止。
+// 由于切片不可比较，多元结果以 *[]T 形式返回，每个结果均为独立切片，可安全保留。
+
+// CrossJoin 返回两个序列的笛卡尔积，q2 在首次遍历时缓存
+func CrossJoin[A, B comparable](q1 Query[A], q2 Query[B]) Query[KV[A, B]] {
+	capHint := q1.capacity * q2.capacity
+	return Query[KV[A, B]]{
+		ctx: firstContext(q1.ctx, q2.ctx),
+		iterate: func(yield func(KV[A, B]) bool) {
+			yield = yieldUntil(firstContext(q1.ctx, q2.ctx), yield)
+			right := q2.ToSlice()
+			if len(right) == 0 {
+				return
+			}
+			for a := range q1.Seq() {
+				for _, b := range right {
+					if !yield(KV[A, B]{Key: a, Value: b}) {
+						return
+					}
+				}
+			}
+		},
+		capacity: capHint,
+	}
+}
+
+// Product 返回多个序列的 n 元笛卡尔积，最右侧序列变化最快
+func Product[T comparable](qs ...Query[T]) Query[*[]T] {
+	return Query[*[]T]{
+		ctx: queriesContext(qs),
+		iterate: func(yield func(*[]T) bool) {
+			yield = yieldUntil(queriesContext(qs), yield)
+			pools := make([][]T, len(qs))
+			for i, q := range qs {
+				pools[i] = q.ToSlice()
+				if len(pools[i]) == 0 {
+					return
+				}
+			}
+			n := len(pools)
+			indices := make([]int, n)
+			for {
+				tuple := make([]T, n)
+				for i, idx := range indices {
+					tuple[i] = pools[i][idx]
+				}
+				if !yield(&tuple) {
+					return
+				}
+				// 里程表式进位
+				i := n - 1
+				for ; i >= 0; i-- {
+					indices[i]++
+					if indices[i] < len(pools[i]) {
+						break
+					}
+					indices[i] = 0
+				}
+				if i < 0 {
+					return
+				}
+			}
+		},
+	}
+}
+
+// Permutations 返回序列中取 k 个元素的全部排列，按输入位置的字典序输出
+func Permutations[T comparable](q Query[T], k int) Query[*[]T] {
+	return Query[*[]T]{
+		ctx: q.ctx,
+		iterate: func(yield func(*[]T) bool) {
+			yield = yieldUntil(q.ctx, yield)
+			pool := q.ToSlice()
+			n := len(pool)
+			if k < 0 || k > n {
+				return
+			}
+			indices := make([]int, n)
+			for i := range indices {
+				indices[i] = i
+			}
+			cycles := make([]int, k)
+			for i := range cycles {
+				cycles[i] = n - i
+			}
+			emit := func() bool {
+				perm := make([]T, k)
+				for i := 0; i < k; i++ {
+					perm[i] = pool[indices[i]]
+				}
+				return yield(&perm)
+			}
+			if !emit() {
+				return
+			}
+			for {
+				i := k - 1
+				for ; i >= 0; i-- {
+					cycles[i]--
+					if cycles[i] == 0 {
+						// 将 indices[i] 移至末尾
+						first := indices[i]
+						copy(indices[i:], indices[i+1:])
+						indices[n-1] = first
+						cycles[i] = n - i
+						continue
+					}
+					j := n - cycles[i]
+					indices[i], indices[j] = indices[j], indices[i]
+					if !emit() {
+						return
+					}
+					break
+				}
+				if i < 0 {
+					return
+				}
+			}
+		},
+	}
+}
+
+// Combinations 返回序列中取 k 个元素的全部组合，按输入位置的字典序输出
+func Combinations[T comparable](q Query[T], k int) Query[*[]T] {
+	return Query[*[]T]{
+		ctx: q.ctx,
+		iterate: func(yield func(*[]T) bool) {
+			yield = yieldUntil(q.ctx, yield)
+			combinations(q.ToSlice(), k, yield)
+		},
+	}
+}
+
+// CombinationsWithReplacement 返回允许元素重复选取的 k 元组合
+func CombinationsWithReplacement[T comparable](q Query[T], k int) Query[*[]T] {
+	return Query[*[]T]{
+		ctx: q.ctx,
+		iterate: func(yield func(*[]T) bool) {
+			yield = yieldUntil(q.ctx, yield)
+			pool := q.ToSlice()
+			n := len(pool)
+			if k < 0 || (n == 0 && k > 0) {
+				return
+			}
+			indices := make([]int, k)
+			for {
+				combo := make([]T, k)
+				for i, idx := range indices {
+					combo[i] = pool[idx]
+				}
+				if !yield(&combo) {
+					return
+				}
+				i := k - 1
+				for ; i >= 0; i-- {
+					if indices[i] != n-1 {
+						break
+					}
+				}
+				if i < 0 {
+					return
+				}
+				next := indices[i] + 1
+				for j := i; j < k; j++ {
+					indices[j] = next
+				}
+			}
+		},
+	}
+}
+
+// PowerSet 返回序列的全部子集，按子集大小递增、同大小按输入位置字典序输出
+func PowerSet[T comparable](q Query[T]) Query[*[]T] {
+	return Query[*[]T]{
+		ctx: q.ctx,
+		iterate: func(yield func(*[]T) bool) {
+			yield = yieldUntil(q.ctx, yield)
+			pool := q.ToSlice()
+			for k := 0; k <= len(pool); k++ {
+				if !combinations(pool, k, yield) {
+					return
+				}
+			}
+		},
+	}
+}
+
+// combinations 生成 pool 中取 k 个元素的组合，返回 false 表示调用方已终止遍历
+func combinations[T any](pool []T, k int, yield func(*[]T) bool) bool {
+	n := len(pool)
+	if k < 0 || k > n {
+		return true
+	}
+	indices := make([]int, k)
+	for i := range indices {
+		indices[i] = i
+	}
+	for {
+		combo := make([]T, k)
+		for i, idx := range indices {
+			combo[i] = pool[idx]
+		}
+		if !yield(&combo) {
+			return false
+		}
+		i := k - 1
+		for ; i >= 0; i-- {
+			if indices[i] != i+n-k {
+				break
+			}
+		}
+		if i < 0 {
+			return true
+		}
+		indices[i]++
+		for j := i + 1; j < k; j++ {
+			indices[j] = indices[j-1] + 1
+		}
+	}
+}
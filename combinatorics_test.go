@@ -0,0 +1,111 @@
+package linq
+
+import (
+	"fmt"
+	"testing"
+)
+
+// 将 *[]T 结果序列格式化便于比较
+func formatTuples[T comparable](q Query[*[]T]) string {
+	var out []string
+	for p := range q.Seq() {
+		out = append(out, fmt.Sprint(*p))
+	}
+	return fmt.Sprint(out)
+}
+
+// TestCrossJoin 测试笛卡尔积
+func TestCrossJoin(t *testing.T) {
+	result := CrossJoin(From([]int{1, 2}), From([]string{"a", "b", "c"})).ToSlice()
+	if len(result) != 6 {
+		t.Fatalf("期望 6 个元素，实际得到 %d", len(result))
+	}
+	if result[0] != (KV[int, string]{1, "a"}) || result[5] != (KV[int, string]{2, "c"}) {
+		t.Errorf("笛卡尔积顺序错误: %v", result)
+	}
+	if got := CrossJoin(From([]int{1, 2}), QueryEmpty[string]()).Count(); got != 0 {
+		t.Errorf("空序列期望 0，实际得到 %d", got)
+	}
+	// 无限左侧序列配合 Take 提前终止
+	if got := CrossJoin(RangeFrom(0, 1), From([]int{0, 1})).Take(5).Count(); got != 5 {
+		t.Errorf("Take 期望 5，实际得到 %d", got)
+	}
+}
+
+// TestProduct 测试 n 元笛卡尔积
+func TestProduct(t *testing.T) {
+	got := formatTuples(Product(From([]int{1, 2}), From([]int{3, 4}), From([]int{5})))
+	if want := "[[1 3 5] [1 4 5] [2 3 5] [2 4 5]]"; got != want {
+		t.Errorf("期望 %s，实际得到 %s", want, got)
+	}
+	if got := Product(From([]int{1}), QueryEmpty[int]()).Count(); got != 0 {
+		t.Errorf("含空序列期望 0，实际得到 %d", got)
+	}
+	if got := formatTuples(Product[int]()); got != "[[]]" {
+		t.Errorf("无参数期望 [[]]，实际得到 %s", got)
+	}
+}
+
+// TestPermutations 测试排列
+func TestPermutations(t *testing.T) {
+	got := formatTuples(Permutations(From([]int{1, 2, 3}), 2))
+	if want := "[[1 2] [1 3] [2 1] [2 3] [3 1] [3 2]]"; got != want {
+		t.Errorf("期望 %s，实际得到 %s", want, got)
+	}
+	if got := Permutations(From([]int{1, 2, 3, 4}), 4).Count(); got != 24 {
+		t.Errorf("4! 期望 24，实际得到 %d", got)
+	}
+	if got := Permutations(From([]int{1, 2}), 3).Count(); got != 0 {
+		t.Errorf("k > n 期望 0，实际得到 %d", got)
+	}
+	if got := formatTuples(Permutations(From([]int{1, 2}), 0)); got != "[[]]" {
+		t.Errorf("k = 0 期望 [[]]，实际得到 %s", got)
+	}
+	// 10! 规模配合 Take 仅生成前 3 个
+	if got := formatTuples(Permutations(QueryRange(0, 10), 10).Take(3)); got != "[[0 1 2 3 4 5 6 7 8 9] [0 1 2 3 4 5 6 7 9 8] [0 1 2 3 4 5 6 8 7 9]]" {
+		t.Errorf("Take 结果错误: %s", got)
+	}
+}
+
+// TestCombinations 测试组合
+func TestCombinations(t *testing.T) {
+	got := formatTuples(Combinations(From([]string{"a", "b", "c", "d"}), 2))
+	if want := "[[a b] [a c] [a d] [b c] [b d] [c d]]"; got != want {
+		t.Errorf("期望 %s，实际得到 %s", want, got)
+	}
+	if got := Combinations(QueryRange(0, 10), 3).Count(); got != 120 {
+		t.Errorf("C(10,3) 期望 120，实际得到 %d", got)
+	}
+	if got := Combinations(From([]int{1}), 2).Count(); got != 0 {
+		t.Errorf("k > n 期望 0，实际得到 %d", got)
+	}
+
+	got = formatTuples(CombinationsWithReplacement(From([]int{1, 2, 3}), 2))
+	if want := "[[1 1] [1 2] [1 3] [2 2] [2 3] [3 3]]"; got != want {
+		t.Errorf("期望 %s，实际得到 %s", want, got)
+	}
+	if got := CombinationsWithReplacement(QueryEmpty[int](), 2).Count(); got != 0 {
+		t.Errorf("空序列期望 0，实际得到 %d", got)
+	}
+}
+
+// TestPowerSet 测试幂集
+func TestPowerSet(t *testing.T) {
+	got := formatTuples(PowerSet(From([]int{1, 2, 3})))
+	if want := "[[] [1] [2] [3] [1 2] [1 3] [2 3] [1 2 3]]"; got != want {
+		t.Errorf("期望 %s，实际得到 %s", want, got)
+	}
+	if got := PowerSet(QueryRange(0, 10)).Count(); got != 1024 {
+		t.Errorf("2^10 期望 1024，实际得到 %d", got)
+	}
+	if got := PowerSet(QueryRange(0, 40)).Take(3).Count(); got != 3 {
+		t.Errorf("Take 期望 3，实际得到 %d", got)
+	}
+
+	// 每个结果均为独立切片
+	subsets := PowerSet(From([]int{1, 2})).ToSlice()
+	(*subsets[1])[0] = 99
+	if (*subsets[3])[0] != 1 {
+		t.Errorf("结果切片不应共享底层数组")
+	}
+}
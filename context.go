@@ -0,0 +1,137 @@
+package linq
+
+import (
+	"context"
+	"iter"
+)
+
+// 上下文传递：WithContext 为查询绑定 ctx，之后的各操作、物化（排序、分组等）及终结操作在 ctx 取消后尽快停止。
+// 取消后得到的结果不完整，通过 Err 或 ToSliceErr 等返回 error 的变体获取 ctx.Err()。
+
+// WithContext 绑定上下文：每取出一个元素时检查 ctx，FromChannel 源在等待接收时也能响应取消。
+// ctx 随后续操作向下游传递；应紧跟数据源调用，位于 WithContext 之前的操作不受其控制
+func (q Query[T]) WithContext(ctx context.Context) Query[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	done := ctx.Done()
+	if done == nil {
+		// 永不取消的上下文无需检查，保留快速路径
+		result := q
+		result.ctx = ctx
+		return result
+	}
+	var iterate iter.Seq[T]
+	if ch := q.channel; ch != nil {
+		stop := q.channelStop
+		iterate = func(yield func(T) bool) {
+			for {
+				select {
+				case <-done:
+					if stop != nil {
+						stop()
+					}
+					return
+				case item, ok := <-ch:
+					if !ok {
+						return
+					}
+					if canceled(ctx) || !yield(item) {
+						if stop != nil {
+							stop()
+						}
+						return
+					}
+				}
+			}
+		}
+	} else {
+		iterate = func(yield func(T) bool) {
+			if canceled(ctx) {
+				return
+			}
+			for item := range q.Seq() {
+				if canceled(ctx) || !yield(item) {
+					return
+				}
+			}
+		}
+	}
+	return Query[T]{
+		ctx:      ctx,
+		compare:  q.compare,
+		capacity: q.capacity,
+		iterate:  iterate,
+	}
+}
+
+// Err 返回绑定上下文的错误，未绑定或未取消时为 nil；在终结操作之后调用，判断结果是否因取消而不完整
+func (q Query[T]) Err() error {
+	if q.ctx == nil {
+		return nil
+	}
+	return q.ctx.Err()
+}
+
+// ToSliceErr 收集为切片，上下文已取消时返回 nil 与 ctx.Err()
+func (q Query[T]) ToSliceErr() ([]T, error) {
+	result := q.ToSlice()
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CountErr 返回元素个数，上下文已取消时返回已计数的个数与 ctx.Err()
+func (q Query[T]) CountErr() (int, error) {
+	count := q.Count()
+	return count, q.Err()
+}
+
+// ForEachErr 遍历元素（action 返回 false 中断），上下文取消时停止并返回 ctx.Err()
+func (q Query[T]) ForEachErr(action func(T) bool) error {
+	q.ForEach(action)
+	return q.Err()
+}
+
+// canceled 非阻塞地检查 ctx 是否已取消，ctx 为 nil 时返回 false
+func canceled(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// yieldUntil 包装 yield，ctx 取消后返回 false；用于输出远多于输入的操作（如组合、窗口）
+func yieldUntil[T any](ctx context.Context, yield func(T) bool) func(T) bool {
+	if ctx == nil || ctx.Done() == nil {
+		return yield
+	}
+	return func(item T) bool {
+		return !canceled(ctx) && yield(item)
+	}
+}
+
+// firstContext 返回第一个非 nil 的上下文，用于合并多个输入的操作
+func firstContext(ctxs ...context.Context) context.Context {
+	for _, ctx := range ctxs {
+		if ctx != nil {
+			return ctx
+		}
+	}
+	return nil
+}
+
+func queriesContext[T comparable](qs []Query[T]) context.Context {
+	for _, q := range qs {
+		if q.ctx != nil {
+			return q.ctx
+		}
+	}
+	return nil
+}
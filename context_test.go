@@ -0,0 +1,149 @@
+package linq
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestWithContextChannel 取消时即使源通道阻塞，排序等物化操作也能及时返回
+func TestWithContextChannel(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		for i := 3; i > 0; i-- {
+			ch <- i
+		}
+		// 之后不再发送也不关闭
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	seen := 0
+	q := FromChannel(ch).WithContext(ctx).Where(func(int) bool {
+		if seen++; seen == 3 {
+			time.AfterFunc(10*time.Millisecond, cancel)
+		}
+		return true
+	})
+	sorted := OrderBy(q, func(i int) int { return i })
+
+	done := make(chan struct{})
+	var got []int
+	var err error
+	go func() {
+		defer close(done)
+		got, err = sorted.ToSliceErr()
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("取消后排序未及时返回")
+	}
+	if !errors.Is(err, context.Canceled) || got != nil {
+		t.Errorf("期望 nil 与 context.Canceled，实际得到 %v 与 %v", got, err)
+	}
+}
+
+// TestWithContextPropagation 上下文随各操作向下游传递
+func TestWithContextPropagation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	q := QueryRange(0, 1_000_000).WithContext(ctx).
+		Where(func(i int) bool { return i%2 == 0 }).
+		Skip(1).
+		Take(900_000)
+	mapped := Select(q, func(i int) string {
+		if calls++; calls == 10 {
+			cancel()
+		}
+		return "x"
+	})
+
+	count, err := mapped.CountErr()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("期望 context.Canceled，实际得到 %v", err)
+	}
+	if count != 10 {
+		t.Errorf("期望取消后立即停止，共 10 个元素，实际得到 %d", count)
+	}
+	if err := Distinct(mapped).Concat(QueryEmpty[string]()).Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("期望多输入操作保留上下文，实际得到 %v", err)
+	}
+}
+
+// TestWithContextGroupBy 分组在取消后不再输出
+func TestWithContextGroupBy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q := QueryRange(0, 100).WithContext(ctx).Where(func(i int) bool {
+		if i == 50 {
+			cancel()
+		}
+		return true
+	})
+	groups := GroupBy(q, func(i int) int { return i % 10 })
+	if n := groups.Count(); n != 0 {
+		t.Errorf("期望取消后不输出分组，实际得到 %d 组", n)
+	}
+	if err := groups.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("期望 context.Canceled，实际得到 %v", err)
+	}
+}
+
+// TestWithContextCombinatorics 输出远多于输入的操作在取消后停止
+func TestWithContextCombinatorics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	count := 0
+	err := Permutations(QueryRange(0, 10).WithContext(ctx), 10).ForEachErr(func(*[]int) bool {
+		if count++; count == 5 {
+			cancel()
+		}
+		return true
+	})
+	if !errors.Is(err, context.Canceled) || count != 5 {
+		t.Errorf("期望 5 个排列后因取消停止，实际得到 %d 个与 %v", count, err)
+	}
+}
+
+// TestWithContextNotCanceled 未取消时结果完整且无错误
+func TestWithContextNotCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got, err := From([]int{2, 3, 1}).WithContext(ctx).Order(func(a, b int) int { return b - a }).
+		Materialize().Skip(1).ToSliceErr()
+	if err != nil || !slices.Equal(got, []int{2, 1}) {
+		t.Errorf("期望 [2 1] 与 nil，实际得到 %v 与 %v", got, err)
+	}
+
+	// 永不取消的上下文保留切片快速路径
+	if q := From([]int{1, 2}).WithContext(context.Background()); q.fastSlice == nil || q.Err() != nil {
+		t.Error("Background 上下文应保留快速路径且无错误")
+	}
+	if err := From([]int{1}).Err(); err != nil {
+		t.Errorf("未绑定上下文时期望 nil，实际得到 %v", err)
+	}
+}
+
+// TestWithContextDefaults ctx 为 nil 的并发及通道操作使用绑定的上下文
+func TestWithContextDefaults(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := QueryRepeat(1, 1_000_000).WithContext(ctx)
+	ch := q.ToChannel(nil)
+	<-ch
+	cancel()
+	n := 0
+	for range ch {
+		n++
+	}
+	if n > 2 {
+		t.Errorf("期望取消后通道很快关闭，实际又收到 %d 个元素", n)
+	}
+
+	processed := 0
+	QueryRepeat(1, 1_000_000).WithContext(ctx).ForEachParallel(func(int) { processed++ }, 1)
+	if processed != 0 {
+		t.Errorf("期望已取消的上下文不再处理元素，实际处理 %d 个", processed)
+	}
+}
@@ -0,0 +1,115 @@
+package linq
+
+import "cmp"
+
+// 按键比较两个快照：DiffBy 以键配对新旧元素，输出新增、删除、修改与未变的条目；
+// DiffSortedBy 要求两侧已按键升序排列，流式归并，只需缓存键相同的一段元素。
+// 同一键出现多次时按出现顺序依次配对，多出的视为新增或删除。
+
+// DiffKind 差异类型
+type DiffKind uint8
+
+const (
+	DiffAdded     DiffKind = iota // 仅新快照中存在，New 有效
+	DiffRemoved                   // 仅旧快照中存在，Old 有效
+	DiffChanged                   // 键相同但内容不同，Old 与 New 均有效
+	DiffUnchanged                 // 键相同且内容相同，Old 与 New 均有效
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	case DiffUnchanged:
+		return "unchanged"
+	}
+	return "unknown"
+}
+
+// DiffEntry 差异条目
+type DiffEntry[T comparable] struct {
+	Kind     DiffKind
+	Old, New T
+}
+
+// pairEntry 比较同键的新旧元素，equal 为 nil 时使用 ==
+func pairEntry[T comparable](before, after T, equal func(a, b T) bool) DiffEntry[T] {
+	same := before == after
+	if equal != nil {
+		same = equal(before, after)
+	}
+	if same {
+		return DiffEntry[T]{Kind: DiffUnchanged, Old: before, New: after}
+	}
+	return DiffEntry[T]{Kind: DiffChanged, Old: before, New: after}
+}
+
+// DiffBy 按 key 比较 before 与 after：先按 after 的顺序输出新增、修改与未变的条目，再按 before 的顺序输出删除的条目。
+// equal 判断同键元素内容是否相同，为 nil 时使用 ==；before 在遍历开始时读入内存，after 流式读取
+func DiffBy[T, K comparable](before, after Query[T], key func(T) K, equal func(a, b T) bool) Query[DiffEntry[T]] {
+	return Query[DiffEntry[T]]{
+		ctx: firstContext(before.ctx, after.ctx),
+		iterate: func(yield func(DiffEntry[T]) bool) {
+			var befores []T
+			index := map[K][]int{}
+			for item := range before.Seq() {
+				k := key(item)
+				index[k] = append(index[k], len(befores))
+				befores = append(befores, item)
+			}
+			matched := make([]bool, len(befores))
+			for item := range after.Seq() {
+				k := key(item)
+				entry := DiffEntry[T]{Kind: DiffAdded, New: item}
+				if queue := index[k]; len(queue) > 0 {
+					index[k] = queue[1:]
+					matched[queue[0]] = true
+					entry = pairEntry(befores[queue[0]], item, equal)
+				}
+				if !yield(entry) {
+					return
+				}
+			}
+			for i, item := range befores {
+				if !matched[i] && !yield(DiffEntry[T]{Kind: DiffRemoved, Old: item}) {
+					return
+				}
+			}
+		},
+		capacity: max(before.capacity, after.capacity),
+	}
+}
+
+// DiffSortedBy 比较两个已按 key 升序排列的快照，按键的顺序流式输出差异条目，同键时依次为配对、删除、新增的条目
+func DiffSortedBy[T comparable, K cmp.Ordered](before, after Query[T], key func(T) K, equal func(a, b T) bool) Query[DiffEntry[T]] {
+	byKey := func(a, b T) int { return cmp.Compare(key(a), key(b)) }
+	return Query[DiffEntry[T]]{
+		ctx: firstContext(before.ctx, after.ctx),
+		iterate: func(yield func(DiffEntry[T]) bool) {
+			mergeRuns(byKey, before, after, mergeUnion, func(befores, afters []T) bool {
+				n := min(len(befores), len(afters))
+				for i := range n {
+					if !yield(pairEntry(befores[i], afters[i], equal)) {
+						return false
+					}
+				}
+				for _, item := range befores[n:] {
+					if !yield(DiffEntry[T]{Kind: DiffRemoved, Old: item}) {
+						return false
+					}
+				}
+				for _, item := range afters[n:] {
+					if !yield(DiffEntry[T]{Kind: DiffAdded, New: item}) {
+						return false
+					}
+				}
+				return true
+			})
+		},
+		capacity: max(before.capacity, after.capacity),
+	}
+}
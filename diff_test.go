@@ -0,0 +1,118 @@
+package linq
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// 测试记录沿用 live_test.go 的编码：id*1000+score，键为 id
+
+func TestDiffBy(t *testing.T) {
+	before := From([]int{liveRec(1, 10), liveRec(2, 20), liveRec(3, 30), liveRec(4, 40)})
+	after := From([]int{liveRec(3, 31), liveRec(5, 50), liveRec(1, 10), liveRec(4, 40)})
+
+	got := DiffBy(before, after, liveID, nil).ToSlice()
+	want := []DiffEntry[int]{
+		{Kind: DiffChanged, Old: liveRec(3, 30), New: liveRec(3, 31)},
+		{Kind: DiffAdded, New: liveRec(5, 50)},
+		{Kind: DiffUnchanged, Old: liveRec(1, 10), New: liveRec(1, 10)},
+		{Kind: DiffUnchanged, Old: liveRec(4, 40), New: liveRec(4, 40)},
+		{Kind: DiffRemoved, Old: liveRec(2, 20)},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("期望 %v，实际得到 %v", want, got)
+	}
+
+	// 自定义 equal：score 相差不超过 5 视为未变
+	near := func(a, b int) bool { return max(liveScore(a), liveScore(b))-min(liveScore(a), liveScore(b)) <= 5 }
+	got = DiffBy(before, after, liveID, near).ToSlice()
+	if got[0].Kind != DiffUnchanged {
+		t.Errorf("期望自定义 equal 下为 unchanged，实际得到 %v", got[0].Kind)
+	}
+
+	// 提前结束
+	if got := DiffBy(before, after, liveID, nil).Take(2).ToSlice(); !slices.Equal(got, want[:2]) {
+		t.Errorf("期望前两项 %v，实际得到 %v", want[:2], got)
+	}
+}
+
+func TestDiffByDuplicateKeys(t *testing.T) {
+	before := From([]int{liveRec(1, 1), liveRec(1, 2), liveRec(2, 1)})
+	after := From([]int{liveRec(1, 1), liveRec(1, 3), liveRec(1, 4)})
+	got := DiffBy(before, after, liveID, nil).ToSlice()
+	want := []DiffEntry[int]{
+		{Kind: DiffUnchanged, Old: liveRec(1, 1), New: liveRec(1, 1)},
+		{Kind: DiffChanged, Old: liveRec(1, 2), New: liveRec(1, 3)},
+		{Kind: DiffAdded, New: liveRec(1, 4)},
+		{Kind: DiffRemoved, Old: liveRec(2, 1)},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("期望按出现顺序配对 %v，实际得到 %v", want, got)
+	}
+}
+
+func TestDiffSortedBy(t *testing.T) {
+	before := From([]int{liveRec(1, 10), liveRec(2, 20), liveRec(4, 40), liveRec(6, 60)})
+	after := From([]int{liveRec(1, 10), liveRec(3, 30), liveRec(4, 41), liveRec(7, 70)})
+	got := DiffSortedBy(before, after, liveID, nil).ToSlice()
+	want := []DiffEntry[int]{
+		{Kind: DiffUnchanged, Old: liveRec(1, 10), New: liveRec(1, 10)},
+		{Kind: DiffRemoved, Old: liveRec(2, 20)},
+		{Kind: DiffAdded, New: liveRec(3, 30)},
+		{Kind: DiffChanged, Old: liveRec(4, 40), New: liveRec(4, 41)},
+		{Kind: DiffRemoved, Old: liveRec(6, 60)},
+		{Kind: DiffAdded, New: liveRec(7, 70)},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("期望 %v，实际得到 %v", want, got)
+	}
+
+	// 与 DiffBy 的结果按种类统计一致
+	count := func(entries []DiffEntry[int]) map[DiffKind]int {
+		m := map[DiffKind]int{}
+		for _, e := range entries {
+			m[e.Kind]++
+		}
+		return m
+	}
+	unordered := count(DiffBy(before, after, liveID, nil).ToSlice())
+	for kind, n := range count(got) {
+		if unordered[kind] != n {
+			t.Errorf("%v：期望 %d 条，实际得到 %d", kind, n, unordered[kind])
+		}
+	}
+}
+
+func TestDiffSortedByStreaming(t *testing.T) {
+	// 无限序列上惰性输出
+	naturals := func(step int) Query[int] {
+		return Query[int]{iterate: func(yield func(int) bool) {
+			for id := 0; ; id += step {
+				if !yield(liveRec(id, 0)) {
+					return
+				}
+			}
+		}}
+	}
+	got := DiffSortedBy(naturals(1), naturals(2), liveID, nil).Take(4).ToSlice()
+	want := []DiffEntry[int]{
+		{Kind: DiffUnchanged, Old: 0, New: 0},
+		{Kind: DiffRemoved, Old: liveRec(1, 0)},
+		{Kind: DiffUnchanged, Old: liveRec(2, 0), New: liveRec(2, 0)},
+		{Kind: DiffRemoved, Old: liveRec(3, 0)},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("期望 %v，实际得到 %v", want, got)
+	}
+}
+
+func TestDiffByContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	before := From([]int{liveRec(1, 1)}).WithContext(ctx)
+	got, err := DiffBy(before, From([]int{liveRec(2, 2)}), liveID, nil).ToSliceErr()
+	if got != nil || err != context.Canceled {
+		t.Errorf("期望上下文随结果传递，取消后返回 nil 与 context.Canceled，实际得到 %v, %v", got, err)
+	}
+}
@@ -0,0 +1,377 @@
+package linq
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 按名称访问字段：路径以 "." 分隔（如 "Dept.Code"），每段可为字段名或 json 标签名，支持嵌入字段与指针；
+// 路径中途遇到 nil 指针时视为空值。类型元数据按类型缓存，未知字段或类型不匹配时返回错误。
+
+var (
+	// ErrUnknownField 字段路径不存在或字段未导出
+	ErrUnknownField = errors.New("linq: unknown field")
+	// ErrFieldType 字段类型与请求的用途不匹配
+	ErrFieldType = errors.New("linq: field type mismatch")
+)
+
+// Pluck 按字段路径投影，字段类型须可赋值给 V；路径中途为 nil 时返回 V 的零值
+func Pluck[T, V comparable](q Query[T], path string) (Query[V], error) {
+	acc, err := accessorFor(reflect.TypeFor[T](), path)
+	if err != nil {
+		return Query[V]{}, err
+	}
+	if vt := reflect.TypeFor[V](); !acc.typ.AssignableTo(vt) {
+		return Query[V]{}, fmt.Errorf("%w: %s is %s, not assignable to %s", ErrFieldType, path, acc.typ, vt)
+	}
+	return Select(q, func(item T) V {
+		var zero V
+		if v, ok := acc.get(reflect.ValueOf(item)); ok {
+			// 接口类型字段为 nil 时断言失败，返回零值
+			x, _ := v.Interface().(V)
+			return x
+		}
+		return zero
+	}), nil
+}
+
+// OrderByField 按字段路径排序，desc 为 true 时降序；支持数值、字符串、布尔、带 Compare 方法的类型（如 time.Time）
+// 及其指针，空值始终排在最后
+func OrderByField[T comparable](q Query[T], path string, desc bool) (Query[T], error) {
+	cmpFn, err := fieldComparator[T](path, desc)
+	if err != nil {
+		return q, err
+	}
+	return orderBy(q, cmpFn), nil
+}
+
+// ThenByField 按字段路径指定次要排序键
+func ThenByField[T comparable](q Query[T], path string, desc bool) (Query[T], error) {
+	cmpFn, err := fieldComparator[T](path, desc)
+	if err != nil || !q.HasOrder() {
+		return q, err
+	}
+	return orderBy(q, cmpFn), nil
+}
+
+// GroupByField 按字段路径分组，字段类型须可比较；路径中途为 nil 时键为 nil
+func GroupByField[T comparable](q Query[T], path string) (Query[*KV[any, []T]], error) {
+	acc, err := accessorFor(reflect.TypeFor[T](), path)
+	if err != nil {
+		return Query[*KV[any, []T]]{}, err
+	}
+	if !acc.typ.Comparable() {
+		return Query[*KV[any, []T]]{}, fmt.Errorf("%w: %s is %s, not comparable", ErrFieldType, path, acc.typ)
+	}
+	return GroupBy(q, func(item T) any {
+		if v, ok := acc.get(reflect.ValueOf(item)); ok {
+			return v.Interface()
+		}
+		return nil
+	}), nil
+}
+
+// ToMapSliceByTag 无需选择器将结构体序列转换为 []map[string]any，键名及取舍遵循 json 标签
+// （"-" 忽略、omitempty 省略零值、未加标签的嵌入结构体字段展开）；嵌套结构体保留原值，nil 元素对应 nil
+func ToMapSliceByTag[T comparable](q Query[T]) ([]map[string]any, error) {
+	info, err := structInfoFor(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]map[string]any, 0, q.capacity)
+	for item := range q.Seq() {
+		v, ok := derefValue(reflect.ValueOf(item))
+		if !ok {
+			result = append(result, nil)
+			continue
+		}
+		m := make(map[string]any, len(info.jsonFields))
+		for _, f := range info.jsonFields {
+			fv, ok := fieldByIndex(v, f.index)
+			if !ok {
+				continue
+			}
+			if f.omitEmpty && (fv.IsZero() || isEmptyContainer(fv)) {
+				continue
+			}
+			m[f.name] = fv.Interface()
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// structInfo 结构体字段元数据
+type structInfo struct {
+	byName     map[string][]int // 字段名及 json 标签名到索引路径，字段名优先
+	jsonFields []jsonField
+}
+
+type jsonField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// fieldAccessor 已解析的字段路径
+type fieldAccessor struct {
+	index []int // 各段索引路径依次拼接，逐级解引用指针
+	typ   reflect.Type
+}
+
+type accessorKey struct {
+	typ  reflect.Type
+	path string
+}
+
+var (
+	structInfoCache sync.Map // reflect.Type -> *structInfo
+	accessorCache   sync.Map // accessorKey -> *fieldAccessor
+)
+
+// structInfoFor 返回结构体（或结构体指针）类型的缓存元数据
+func structInfoFor(t reflect.Type) (*structInfo, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrFieldType, t)
+	}
+	if info, ok := structInfoCache.Load(t); ok {
+		return info.(*structInfo), nil
+	}
+	info := &structInfo{byName: make(map[string][]int)}
+	info.jsonFields = collectJSONFields(t)
+	for _, f := range info.jsonFields {
+		info.byName[f.name] = f.index
+	}
+	for _, f := range reflect.VisibleFields(t) {
+		if f.IsExported() && readablePath(t, f.Index) {
+			info.byName[f.Name] = f.Index
+		}
+	}
+	actual, _ := structInfoCache.LoadOrStore(t, info)
+	return actual.(*structInfo), nil
+}
+
+// collectJSONFields 按 encoding/json 的规则收集字段：同名时层级较浅者优先，同层级取先出现者
+func collectJSONFields(t reflect.Type) []jsonField {
+	type candidate struct {
+		jsonField
+		depth int
+	}
+	var candidates []candidate
+	var walk func(t reflect.Type, prefix []int, depth int)
+	walk = func(t reflect.Type, prefix []int, depth int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			index := append(append([]int(nil), prefix...), i)
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+				// 与 encoding/json 一致：忽略指向未导出结构体的嵌入指针
+				if f.IsExported() || f.Type.Kind() != reflect.Pointer {
+					walk(ft, index, depth+1)
+				}
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			candidates = append(candidates, candidate{jsonField{name, index, strings.Contains(","+opts+",", ",omitempty,")}, depth})
+		}
+	}
+	walk(t, nil, 0)
+
+	best := make(map[string]int, len(candidates))
+	for i, c := range candidates {
+		if j, ok := best[c.name]; !ok || c.depth < candidates[j].depth {
+			best[c.name] = i
+		}
+	}
+	fields := make([]jsonField, 0, len(best))
+	for i, c := range candidates {
+		if best[c.name] == i {
+			fields = append(fields, c.jsonField)
+		}
+	}
+	return fields
+}
+
+// accessorFor 解析并缓存字段路径
+func accessorFor(t reflect.Type, path string) (*fieldAccessor, error) {
+	key := accessorKey{t, path}
+	if acc, ok := accessorCache.Load(key); ok {
+		return acc.(*fieldAccessor), nil
+	}
+	acc := &fieldAccessor{typ: t}
+	for _, name := range strings.Split(path, ".") {
+		info, err := structInfoFor(acc.typ)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s in %s", ErrUnknownField, name, path)
+		}
+		index, ok := info.byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s in %s", ErrUnknownField, name, path)
+		}
+		acc.index = append(acc.index, index...)
+		acc.typ = typeByIndex(acc.typ, index)
+	}
+	actual, _ := accessorCache.LoadOrStore(key, acc)
+	return actual.(*fieldAccessor), nil
+}
+
+// readablePath 判断索引路径能否通过反射读取：不能经过指向未导出结构体的嵌入指针
+func readablePath(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		f := t.Field(i)
+		if !f.IsExported() && f.Type.Kind() == reflect.Pointer {
+			return false
+		}
+		t = f.Type
+	}
+	return true
+}
+
+func typeByIndex(t reflect.Type, index []int) reflect.Type {
+	for _, i := range index {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		t = t.Field(i).Type
+	}
+	return t
+}
+
+// get 按索引路径取值，途经 nil 指针时返回 false
+func (a *fieldAccessor) get(v reflect.Value) (reflect.Value, bool) {
+	return fieldByIndex(v, a.index)
+}
+
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		var ok bool
+		if v, ok = derefValue(v); !ok {
+			return reflect.Value{}, false
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
+func derefValue(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, v.IsValid()
+}
+
+func isEmptyContainer(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// fieldComparator 按字段类型生成比较器，空值（nil 指针或路径中途为 nil）排在最后
+func fieldComparator[T comparable](path string, desc bool) (CompareFunc[T], error) {
+	acc, err := accessorFor(reflect.TypeFor[T](), path)
+	if err != nil {
+		return nil, err
+	}
+	valueCmp := valueComparator(acc.typ)
+	if valueCmp == nil {
+		return nil, fmt.Errorf("%w: %s is %s, not ordered", ErrFieldType, path, acc.typ)
+	}
+	return func(a, b T) int {
+		va, okA := acc.get(reflect.ValueOf(a))
+		if okA {
+			va, okA = derefValue(va)
+		}
+		vb, okB := acc.get(reflect.ValueOf(b))
+		if okB {
+			vb, okB = derefValue(vb)
+		}
+		switch {
+		case !okA && !okB:
+			return 0
+		case !okA:
+			return 1
+		case !okB:
+			return -1
+		case desc:
+			return valueCmp(vb, va)
+		}
+		return valueCmp(va, vb)
+	}, nil
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// valueAs 取出具体类型的值，可寻址时经指针读取以避免装箱
+func valueAs[V any](v reflect.Value) V {
+	if v.CanAddr() {
+		return *v.Addr().Interface().(*V)
+	}
+	return v.Interface().(V)
+}
+
+// valueComparator 返回解引用后字段值的比较函数，类型不可排序时返回 nil
+func valueComparator(t reflect.Type) func(a, b reflect.Value) int {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return func(a, b reflect.Value) int { return valueAs[time.Time](a).Compare(valueAs[time.Time](b)) }
+	}
+	// 其他带 Compare 方法的类型经反射调用，较慢
+	if m, ok := t.MethodByName("Compare"); ok &&
+		m.Type.NumIn() == 2 && m.Type.In(1) == t && m.Type.NumOut() == 1 && m.Type.Out(0).Kind() == reflect.Int {
+		return func(a, b reflect.Value) int {
+			return int(m.Func.Call([]reflect.Value{a, b})[0].Int())
+		}
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b reflect.Value) int { return cmp.Compare(a.Int(), b.Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b reflect.Value) int { return cmp.Compare(a.Uint(), b.Uint()) }
+	case reflect.Float32, reflect.Float64:
+		return func(a, b reflect.Value) int { return cmp.Compare(a.Float(), b.Float()) }
+	case reflect.String:
+		return func(a, b reflect.Value) int { return cmp.Compare(a.String(), b.String()) }
+	case reflect.Bool:
+		return func(a, b reflect.Value) int {
+			switch {
+			case a.Bool() == b.Bool():
+				return 0
+			case a.Bool():
+				return 1
+			}
+			return -1
+		}
+	}
+	return nil
+}
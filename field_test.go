@@ -0,0 +1,214 @@
+package linq
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+type fieldDept struct {
+	Code string `json:"code"`
+}
+
+type fieldAudit struct {
+	CreatedBy string `json:"created_by"`
+	note      string
+}
+
+type fieldMember struct {
+	fieldAudit
+	Name     string     `json:"name"`
+	Age      int        `json:"age,omitempty"`
+	Dept     *fieldDept `json:"dept"`
+	Joined   *time.Time `json:"joined,omitempty"`
+	Password string     `json:"-"`
+	Tags     []string   `json:"tags,omitempty"`
+	secret   int
+}
+
+func fieldMembers() []*fieldMember {
+	day := func(d int) *time.Time {
+		t := time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	return []*fieldMember{
+		{Name: "a", Age: 30, Dept: &fieldDept{"rd"}, Joined: day(3), fieldAudit: fieldAudit{CreatedBy: "x"}},
+		{Name: "b", Age: 20, Dept: nil, Joined: day(1)},
+		{Name: "c", Age: 25, Dept: &fieldDept{"ops"}},
+		{Name: "d", Age: 20, Dept: &fieldDept{"rd"}, Joined: day(2)},
+	}
+}
+
+// TestPluck 测试按字段路径投影
+func TestPluck(t *testing.T) {
+	q := From(fieldMembers())
+	names, err := Pluck[*fieldMember, string](q, "Name")
+	if err != nil || !slices.Equal(names.ToSlice(), []string{"a", "b", "c", "d"}) {
+		t.Errorf("Pluck(Name) 错误: %v %v", names.ToSlice(), err)
+	}
+	codes, err := Pluck[*fieldMember, string](q, "Dept.Code")
+	if err != nil || !slices.Equal(codes.ToSlice(), []string{"rd", "", "ops", "rd"}) {
+		t.Errorf("Pluck(Dept.Code) 路径中途为 nil 时应为零值: %v %v", codes.ToSlice(), err)
+	}
+	byTag, err := Pluck[*fieldMember, string](q, "dept.code")
+	if err != nil || !slices.Equal(byTag.ToSlice(), codes.ToSlice()) {
+		t.Errorf("json 标签名路径错误: %v", err)
+	}
+	creators, err := Pluck[*fieldMember, any](q, "CreatedBy")
+	if err != nil || creators.First() != "x" {
+		t.Errorf("嵌入字段投影错误: %v", err)
+	}
+
+	// 接口类型字段为 nil 时返回零值
+	type withIface struct {
+		Val any
+		Err error
+	}
+	ifaces := From([]*withIface{{Val: 1, Err: ErrFieldType}, {}})
+	vals, err := Pluck[*withIface, any](ifaces, "Val")
+	if err != nil || !slices.Equal(vals.ToSlice(), []any{1, nil}) {
+		t.Errorf("Pluck(Val) 期望 [1 <nil>]，实际得到 %v %v", vals.ToSlice(), err)
+	}
+	errs, err := Pluck[*withIface, error](ifaces, "Err")
+	if err != nil || !slices.Equal(errs.ToSlice(), []error{ErrFieldType, nil}) {
+		t.Errorf("Pluck(Err) 期望 [ErrFieldType <nil>]，实际得到 %v %v", errs.ToSlice(), err)
+	}
+
+	if _, err := Pluck[*fieldMember, string](q, "Nope"); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("未知字段应返回 ErrUnknownField，实际得到 %v", err)
+	}
+	if _, err := Pluck[*fieldMember, int](q, "secret"); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("未导出字段应返回 ErrUnknownField，实际得到 %v", err)
+	}
+	if _, err := Pluck[*fieldMember, string](q, "Name.X"); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("非结构体字段的子路径应返回 ErrUnknownField，实际得到 %v", err)
+	}
+	if _, err := Pluck[*fieldMember, string](q, "Age"); !errors.Is(err, ErrFieldType) {
+		t.Errorf("类型不匹配应返回 ErrFieldType，实际得到 %v", err)
+	}
+	if _, err := Pluck[int, int](From([]int{1}), "X"); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("非结构体类型应返回错误，实际得到 %v", err)
+	}
+}
+
+// TestOrderByField 测试按字段路径排序
+func TestOrderByField(t *testing.T) {
+	q := From(fieldMembers())
+	names := func(q Query[*fieldMember]) []string {
+		return Select(q, func(m *fieldMember) string { return m.Name }).ToSlice()
+	}
+
+	byAge, err := OrderByField(q, "Age", false)
+	if err != nil || !slices.Equal(names(byAge), []string{"b", "d", "c", "a"}) {
+		t.Errorf("OrderByField(Age) 错误: %v %v", names(byAge), err)
+	}
+	then, err := ThenByField(byAge, "Name", true)
+	if err != nil || !slices.Equal(names(then), []string{"d", "b", "c", "a"}) {
+		t.Errorf("ThenByField(Name, desc) 错误: %v %v", names(then), err)
+	}
+	byJoined, err := OrderByField(q, "Joined", true)
+	if err != nil || !slices.Equal(names(byJoined), []string{"a", "d", "b", "c"}) {
+		t.Errorf("按 *time.Time 降序应空值在后: %v %v", names(byJoined), err)
+	}
+	byCode, err := OrderByField(q, "Dept.Code", false)
+	if err != nil || !slices.Equal(names(byCode), []string{"c", "a", "d", "b"}) {
+		t.Errorf("按嵌套字段排序错误: %v %v", names(byCode), err)
+	}
+
+	if _, err := OrderByField(q, "Tags", false); !errors.Is(err, ErrFieldType) {
+		t.Errorf("不可排序字段应返回 ErrFieldType，实际得到 %v", err)
+	}
+	if _, err := OrderByField(q, "Missing", false); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("未知字段应返回 ErrUnknownField，实际得到 %v", err)
+	}
+}
+
+// TestOrderByFieldTime time.Time 字段不经反射调用比较
+func TestOrderByFieldTime(t *testing.T) {
+	type event struct {
+		Name string
+		At   time.Time
+	}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []event{{"b", base.Add(time.Hour)}, {"c", base.Add(2 * time.Hour)}, {"a", base}}
+	sorted, err := OrderByField(From(events), "At", false)
+	got := Select(sorted, func(e event) string { return e.Name }).ToSlice()
+	if err != nil || !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("按 time.Time 值字段排序错误: %v %v", got, err)
+	}
+
+	cmpFn, err := fieldComparator[*fieldMember]("Joined", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	members := fieldMembers()
+	allocs := testing.AllocsPerRun(100, func() { cmpFn(members[0], members[1]) })
+	if allocs != 0 || cmpFn(members[0], members[1]) <= 0 {
+		t.Errorf("期望 *time.Time 字段比较无内存分配，实际得到 %v 次", allocs)
+	}
+}
+
+// TestGroupByField 测试按字段路径分组
+func TestGroupByField(t *testing.T) {
+	groups, err := GroupByField(From(fieldMembers()), "Dept.Code")
+	if err != nil {
+		t.Fatalf("GroupByField 错误: %v", err)
+	}
+	counts := map[any]int{}
+	for g := range groups.Seq() {
+		counts[g.Key] = len(g.Value)
+	}
+	if counts["rd"] != 2 || counts["ops"] != 1 || counts[nil] != 1 {
+		t.Errorf("分组结果错误: %v", counts)
+	}
+	if _, err := GroupByField(From(fieldMembers()), "Tags"); !errors.Is(err, ErrFieldType) {
+		t.Errorf("不可比较字段应返回 ErrFieldType，实际得到 %v", err)
+	}
+}
+
+// TestToMapSliceByTag 测试按 json 标签转换为 map
+func TestToMapSliceByTag(t *testing.T) {
+	members := fieldMembers()
+	members[2].Tags = []string{"on-call"}
+	maps, err := ToMapSliceByTag(From(append(members, nil)))
+	if err != nil {
+		t.Fatalf("ToMapSliceByTag 错误: %v", err)
+	}
+	if len(maps) != 5 || maps[4] != nil {
+		t.Fatalf("nil 元素应对应 nil，实际得到 %v", maps)
+	}
+	first := maps[0]
+	if first["name"] != "a" || first["age"] != 30 || first["created_by"] != "x" || first["dept"] != members[0].Dept {
+		t.Errorf("字段值错误: %v", first)
+	}
+	for _, key := range []string{"Password", "secret", "note", "tags"} {
+		if _, ok := first[key]; ok {
+			t.Errorf("不应包含 %s: %v", key, first)
+		}
+	}
+	if _, ok := maps[2]["joined"]; ok {
+		t.Errorf("omitempty 的 nil 指针应省略: %v", maps[2])
+	}
+	if _, ok := maps[2]["tags"]; !ok {
+		t.Errorf("非空切片应保留: %v", maps[2])
+	}
+	if _, err := ToMapSliceByTag(From([]string{"x"})); !errors.Is(err, ErrFieldType) {
+		t.Errorf("非结构体应返回 ErrFieldType，实际得到 %v", err)
+	}
+}
+
+// TestFieldMetadataCache 测试元数据缓存
+func TestFieldMetadataCache(t *testing.T) {
+	a, err1 := accessorFor(reflect.TypeFor[*fieldMember](), "Dept.Code")
+	b, err2 := accessorFor(reflect.TypeFor[*fieldMember](), "Dept.Code")
+	if err1 != nil || err2 != nil || a != b {
+		t.Errorf("相同类型与路径应复用缓存的访问器")
+	}
+	i1, _ := structInfoFor(reflect.TypeFor[fieldMember]())
+	i2, _ := structInfoFor(reflect.TypeFor[*fieldMember]())
+	if i1 != i2 {
+		t.Errorf("结构体与其指针应共享元数据")
+	}
+}
@@ -0,0 +1,160 @@
+package linq
+
+import (
+	"time"
+)
+
+// Generate 通过生成函数创建惰性序列，生成函数返回 false 时结束
+func Generate[T comparable](generator func() (T, bool)) Query[T] {
+	return Query[T]{
+		iterate: func(yield func(T) bool) {
+			for {
+				item, ok := generator()
+				if !ok || !yield(item) {
+					return
+				}
+			}
+		},
+	}
+}
+
+// Iterate 从种子开始不断应用 next 生成无限序列：seed, next(seed), next(next(seed))...
+func Iterate[T comparable](seed T, next func(T) T) Query[T] {
+	return Query[T]{
+		iterate: func(yield func(T) bool) {
+			for item := seed; ; item = next(item) {
+				if !yield(item) {
+					return
+				}
+			}
+		},
+	}
+}
+
+// Unfold 从初始状态展开序列，每一步返回元素、下一个状态以及是否继续
+func Unfold[T comparable, S any](seed S, fn func(S) (T, S, bool)) Query[T] {
+	return Query[T]{
+		iterate: func(yield func(T) bool) {
+			state := seed
+			for {
+				item, next, ok := fn(state)
+				if !ok || !yield(item) {
+					return
+				}
+				state = next
+			}
+		},
+	}
+}
+
+// Cycle 无限重复序列，首轮遍历时缓存元素，空序列返回空
+func Cycle[T comparable](q Query[T]) Query[T] {
+	return Query[T]{
+		ctx: q.ctx,
+		iterate: func(yield func(T) bool) {
+			var items []T
+			if q.capacity > 0 {
+				items = make([]T, 0, q.capacity)
+			}
+			for item := range q.Seq() {
+				items = append(items, item)
+				if !yield(item) {
+					return
+				}
+			}
+			if len(items) == 0 {
+				return
+			}
+			for {
+				for _, item := range items {
+					if !yield(item) {
+						return
+					}
+				}
+			}
+		},
+	}
+}
+
+// RangeStep 创建 [start, end) 区间内按 step 递增（step 为负则递减）的序列，step 为 0 时返回空
+func RangeStep[T Integer | Float](start, end, step T) Query[T] {
+	if step == 0 || (step > 0 && start >= end) || (step < 0 && start <= end) {
+		return QueryEmpty[T]()
+	}
+	if isFloat[T]() {
+		return Query[T]{
+			iterate: func(yield func(T) bool) {
+				// 以 start + i*step 计算，避免浮点累加误差；计数用 int，大数值时不会因精度不足停止递增
+				for i := 0; ; i++ {
+					v := start + T(i)*step
+					if (step > 0 && v >= end) || (step < 0 && v <= end) {
+						return
+					}
+					if !yield(v) {
+						return
+					}
+				}
+			},
+		}
+	}
+	return Query[T]{
+		iterate: func(yield func(T) bool) {
+			for v := start; ; {
+				if !yield(v) {
+					return
+				}
+				// 下一个值越界或溢出回绕时结束
+				next := v + step
+				if step > 0 && (next <= v || next >= end) || step < 0 && (next >= v || next <= end) {
+					return
+				}
+				v = next
+			}
+		},
+	}
+}
+
+// isFloat 判断 T 是否为浮点类型：整数类型的 1/2 为 0
+func isFloat[T Integer | Float]() bool {
+	var half T = 1
+	half /= 2
+	return half != 0
+}
+
+// RangeFrom 创建从 start 开始按 step 无限递增的序列，需配合 Take/TakeWhile 使用
+func RangeFrom[T Integer | Float](start, step T) Query[T] {
+	return Query[T]{
+		iterate: func(yield func(T) bool) {
+			for i := 0; ; i++ {
+				if !yield(start + T(i)*step) {
+					return
+				}
+			}
+		},
+	}
+}
+
+// RangeTime 创建 [start, end) 区间内按 step 间隔的时间序列，step 为 0 时返回空
+func RangeTime(start, end time.Time, step time.Duration) Query[time.Time] {
+	if step == 0 || (step > 0 && !start.Before(end)) || (step < 0 && !start.After(end)) {
+		return QueryEmpty[time.Time]()
+	}
+	return Query[time.Time]{
+		iterate: func(yield func(time.Time) bool) {
+			for i := time.Duration(0); ; i++ {
+				v := start.Add(i * step)
+				if (step > 0 && !v.Before(end)) || (step < 0 && !v.After(end)) {
+					return
+				}
+				if !yield(v) {
+					return
+				}
+			}
+		},
+	}
+}
+
+// RangeTimeFrom 创建从 start 开始按 step 间隔的无限时间序列
+func RangeTimeFrom(start time.Time, step time.Duration) Query[time.Time] {
+	return Iterate(start, func(t time.Time) time.Time { return t.Add(step) })
+}
@@ -0,0 +1,150 @@
+package linq
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestGenerate 测试生成函数序列
+func TestGenerate(t *testing.T) {
+	n := 0
+	result := Generate(func() (int, bool) {
+		n++
+		return n, n <= 3
+	}).ToSlice()
+	if !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("期望 [1 2 3]，实际得到 %v", result)
+	}
+
+	calls := 0
+	infinite := Generate(func() (int, bool) {
+		calls++
+		return calls, true
+	})
+	if got := infinite.Take(5).ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Take 期望 [1 2 3 4 5]，实际得到 %v", got)
+	}
+	if calls != 5 {
+		t.Errorf("惰性求值期望调用 5 次，实际 %d 次", calls)
+	}
+}
+
+// TestIterate 测试迭代序列
+func TestIterate(t *testing.T) {
+	result := Iterate(1, func(i int) int { return i * 2 }).Take(6).ToSlice()
+	if !slices.Equal(result, []int{1, 2, 4, 8, 16, 32}) {
+		t.Errorf("期望 [1 2 4 8 16 32]，实际得到 %v", result)
+	}
+	result = Iterate(1, func(i int) int { return i + 3 }).TakeWhile(func(i int) bool { return i < 10 }).ToSlice()
+	if !slices.Equal(result, []int{1, 4, 7}) {
+		t.Errorf("期望 [1 4 7]，实际得到 %v", result)
+	}
+}
+
+// TestUnfold 测试展开序列
+func TestUnfold(t *testing.T) {
+	type pair struct{ a, b int }
+	fib := Unfold(pair{0, 1}, func(s pair) (int, pair, bool) {
+		return s.a, pair{s.b, s.a + s.b}, true
+	})
+	if got := fib.Take(8).ToSlice(); !slices.Equal(got, []int{0, 1, 1, 2, 3, 5, 8, 13}) {
+		t.Errorf("斐波那契期望 [0 1 1 2 3 5 8 13]，实际得到 %v", got)
+	}
+
+	countdown := Unfold(3, func(s int) (string, int, bool) {
+		return string(rune('a' + s)), s - 1, s > 0
+	}).ToSlice()
+	if !slices.Equal(countdown, []string{"d", "c", "b"}) {
+		t.Errorf("期望 [d c b]，实际得到 %v", countdown)
+	}
+}
+
+// TestCycle 测试无限重复序列
+func TestCycle(t *testing.T) {
+	result := Cycle(From([]int{1, 2, 3})).Take(7).ToSlice()
+	if !slices.Equal(result, []int{1, 2, 3, 1, 2, 3, 1}) {
+		t.Errorf("期望 [1 2 3 1 2 3 1]，实际得到 %v", result)
+	}
+	if got := Cycle(QueryEmpty[int]()).Take(3).Count(); got != 0 {
+		t.Errorf("空序列期望 0 个元素，实际得到 %d", got)
+	}
+
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	if got := Cycle(FromChannel(ch)).Take(5).ToSlice(); !slices.Equal(got, []int{1, 2, 1, 2, 1}) {
+		t.Errorf("Channel 源期望 [1 2 1 2 1]，实际得到 %v", got)
+	}
+}
+
+// TestRangeStep 测试步长序列
+func TestRangeStep(t *testing.T) {
+	if got := RangeStep(0, 10, 3).ToSlice(); !slices.Equal(got, []int{0, 3, 6, 9}) {
+		t.Errorf("期望 [0 3 6 9]，实际得到 %v", got)
+	}
+	if got := RangeStep(5, 0, -2).ToSlice(); !slices.Equal(got, []int{5, 3, 1}) {
+		t.Errorf("期望 [5 3 1]，实际得到 %v", got)
+	}
+	if got := RangeStep(0.0, 0.5, 0.1).Count(); got != 5 {
+		t.Errorf("浮点步长期望 5 个元素，实际得到 %d", got)
+	}
+	if got := RangeStep(0, 10, 0).Count(); got != 0 {
+		t.Errorf("步长为 0 期望空序列，实际得到 %d", got)
+	}
+	if got := RangeStep(0, 10, -1).Count(); got != 0 {
+		t.Errorf("方向相反期望空序列，实际得到 %d", got)
+	}
+	durations := RangeStep(time.Second, 4*time.Second, time.Second).ToSlice()
+	if !slices.Equal(durations, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}) {
+		t.Errorf("Duration 序列错误: %v", durations)
+	}
+	if got := RangeFrom(10, 5).Take(3).ToSlice(); !slices.Equal(got, []int{10, 15, 20}) {
+		t.Errorf("期望 [10 15 20]，实际得到 %v", got)
+	}
+}
+
+// TestRangeStepNarrowTypes 窄整数不溢出回绕，float32 大数值时仍能结束
+func TestRangeStepNarrowTypes(t *testing.T) {
+	if got := RangeStep[uint8](250, 255, 3).ToSlice(); !slices.Equal(got, []uint8{250, 253}) {
+		t.Errorf("期望 [250 253]，实际得到 %v", got)
+	}
+	if got := RangeStep[int8](0, 127, 100).ToSlice(); !slices.Equal(got, []int8{0, 100}) {
+		t.Errorf("期望 [0 100]，实际得到 %v", got)
+	}
+	if got := RangeStep[int8](-100, -128, -20).ToSlice(); !slices.Equal(got, []int8{-100, -120}) {
+		t.Errorf("期望 [-100 -120]，实际得到 %v", got)
+	}
+	if got := RangeStep[uint8](0, 255, 1).Count(); got != 255 {
+		t.Errorf("期望 255 个元素，实际得到 %d", got)
+	}
+	// 超过 2^24 后 float32 精度不足，值会取整，但序列仍能结束且不越过上界
+	got := RangeStep[float32](16777214, 16777220, 1).ToSlice()
+	if len(got) == 0 || slices.Max(got) >= 16777220 {
+		t.Errorf("float32 期望元素小于上界，实际得到 %v", got)
+	}
+	if n := RangeStep[float32](0, 2e7, 1).Count(); n < 2e7-2 || n > 2e7 {
+		t.Errorf("float32 期望约 20000000 个元素，实际得到 %d", n)
+	}
+}
+
+// TestRangeTime 测试时间序列
+func TestRangeTime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+	result := RangeTime(start, end, time.Hour).ToSlice()
+	if len(result) != 3 || !result[2].Equal(start.Add(2*time.Hour)) {
+		t.Errorf("期望 3 个整点时间，实际得到 %v", result)
+	}
+	if got := RangeTime(end, start, -time.Hour).Count(); got != 3 {
+		t.Errorf("递减期望 3 个元素，实际得到 %d", got)
+	}
+	if got := RangeTime(start, end, 0).Count(); got != 0 {
+		t.Errorf("步长为 0 期望空序列，实际得到 %d", got)
+	}
+	days := RangeTimeFrom(start, 24*time.Hour).Take(3).ToSlice()
+	if len(days) != 3 || days[2].Day() != 3 {
+		t.Errorf("期望连续 3 天，实际得到 %v", days)
+	}
+}
@@ -3,7 +3,9 @@
 package linq
 
 import (
+	"cmp"
 	"context"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -550,3 +552,55 @@ func BenchmarkOrderedQueryReverse(b *testing.B) {
 		q.Order(Asc(func(i int) int { return i })).Reverse().ToSlice()
 	}
 }
+
+// BenchmarkOrderedQueryParallel 基准测试：大数据量多级排序，顺序与并行对比
+func BenchmarkOrderedQueryParallel(b *testing.B) {
+	data := make([]int, 1_000_000)
+	for i := range data {
+		data[i] = (i * 7919) % 1_000_003
+	}
+	q := From(data)
+	high := Asc(func(i int) int { return i / 1000 })
+	low := Desc(func(i int) int { return i % 1000 })
+	b.Run("Sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			slices.SortStableFunc(slices.Clone(data), composeComparators([]CompareFunc[int]{high, low}))
+		}
+	})
+	b.Run("Parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			q.Order(high).Then(low).Parallel().ToSlice()
+		}
+	})
+}
+
+// BenchmarkOrderByRadix 基准测试：整数与字符串键的基数排序与比较排序对比
+func BenchmarkOrderByRadix(b *testing.B) {
+	data := make([]int, 100_000)
+	for i := range data {
+		data[i] = (i * 7919) % 1_000_003
+	}
+	intKey := func(i int) int64 { return int64(i) * 1000 }
+	strKey := func(i int) string { return strings.Repeat("k", i%4) + string(rune('a'+i%26)) + string(rune('a'+i%17)) }
+	q := From(data)
+	b.Run("Int/Radix", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			OrderBy(q, intKey).ToSlice()
+		}
+	})
+	b.Run("Int/SortStableFunc", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			slices.SortStableFunc(slices.Clone(data), func(x, y int) int { return cmp.Compare(intKey(x), intKey(y)) })
+		}
+	})
+	b.Run("String/Radix", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			OrderBy(q, strKey).ToSlice()
+		}
+	})
+	b.Run("String/SortStableFunc", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			slices.SortStableFunc(slices.Clone(data), func(x, y int) int { return cmp.Compare(strKey(x), strKey(y)) })
+		}
+	})
+}
@@ -0,0 +1,490 @@
+package linq
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// 实时查询：LiveCollection 按键保存记录，Add、Update、Remove 产生变更事件，
+// 已注册的实时查询（过滤集合、计数、求和、最值、分组统计）据此增量更新结果，不重新遍历全部记录。
+// 变更方法在返回前同步通知订阅者，回调在集合的锁内执行，不可在回调中修改集合或读取实时查询。
+
+// ChangeKind 变更类型
+type ChangeKind uint8
+
+const (
+	ChangeAdded   ChangeKind = iota // 新增，New 有效
+	ChangeRemoved                   // 删除，Old 有效
+	ChangeUpdated                   // 修改，Old 与 New 均有效
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeUpdated:
+		return "updated"
+	}
+	return "unknown"
+}
+
+// Change 记录的变更
+type Change[T any] struct {
+	Kind     ChangeKind
+	Old, New T
+}
+
+// Delta 实时值的变化
+type Delta[V any] struct {
+	Old, New V
+}
+
+// GroupDelta 分组统计的变化：分组出现时为 ChangeAdded，分组不再有记录时为 ChangeRemoved
+type GroupDelta[G, V any] struct {
+	Kind     ChangeKind
+	Key      G
+	Old, New V
+}
+
+// subscribers 订阅者列表，由所属集合的锁保护
+type subscribers[E any] struct {
+	next int
+	fns  map[int]func(E)
+}
+
+// add 添加订阅者，返回在 mu 下删除该订阅者的函数
+func (s *subscribers[E]) add(mu *sync.Mutex, fn func(E)) func() {
+	if s.fns == nil {
+		s.fns = map[int]func(E){}
+	}
+	id := s.next
+	s.next++
+	s.fns[id] = fn
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(s.fns, id)
+	}
+}
+
+func (s *subscribers[E]) notify(event E) {
+	for _, id := range slices.Sorted(maps.Keys(s.fns)) {
+		s.fns[id](event)
+	}
+}
+
+// LiveCollection 可观察的记录集合，记录由 key 标识
+type LiveCollection[K comparable, T comparable] struct {
+	mu    sync.Mutex
+	key   func(T) K
+	items map[K]T
+	subs  subscribers[Change[T]]
+}
+
+// NewLiveCollection 创建以 key 标识记录的集合，items 中键重复时保留后者
+func NewLiveCollection[K comparable, T comparable](key func(T) K, items ...T) *LiveCollection[K, T] {
+	c := &LiveCollection[K, T]{key: key, items: make(map[K]T, len(items))}
+	for _, item := range items {
+		c.items[key(item)] = item
+	}
+	return c
+}
+
+// Add 添加记录，键已存在时返回 false
+func (c *LiveCollection[K, T]) Add(item T) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	k := c.key(item)
+	if _, ok := c.items[k]; ok {
+		return false
+	}
+	c.items[k] = item
+	c.subs.notify(Change[T]{Kind: ChangeAdded, New: item})
+	return true
+}
+
+// Update 替换键相同的记录，键不存在时返回 false；记录未变化时不产生事件
+func (c *LiveCollection[K, T]) Update(item T) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	k := c.key(item)
+	old, ok := c.items[k]
+	if !ok {
+		return false
+	}
+	if old != item {
+		c.items[k] = item
+		c.subs.notify(Change[T]{Kind: ChangeUpdated, Old: old, New: item})
+	}
+	return true
+}
+
+// Remove 删除键对应的记录并返回该记录，键不存在时返回 false
+func (c *LiveCollection[K, T]) Remove(key K) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old, ok := c.items[key]
+	if ok {
+		delete(c.items, key)
+		c.subs.notify(Change[T]{Kind: ChangeRemoved, Old: old})
+	}
+	return old, ok
+}
+
+// Get 返回键对应的记录
+func (c *LiveCollection[K, T]) Get(key K) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[key]
+	return item, ok
+}
+
+// Len 返回记录个数
+func (c *LiveCollection[K, T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Query 返回当前全部记录的快照查询，顺序不固定
+func (c *LiveCollection[K, T]) Query() Query[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return From(slices.Collect(maps.Values(c.items)))
+}
+
+// Subscribe 订阅记录变更，返回取消订阅的函数
+func (c *LiveCollection[K, T]) Subscribe(fn func(Change[T])) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.subs.add(&c.mu, fn)
+}
+
+// attach 以当前记录初始化实时查询并订阅后续变更，均在锁内完成，不会遗漏或重复变更
+func (c *LiveCollection[K, T]) attach(init func(T), apply func(Change[T])) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, item := range c.items {
+		init(item)
+	}
+	return c.subs.add(&c.mu, apply)
+}
+
+// LiveSet 实时过滤集合
+type LiveSet[K comparable, T comparable] struct {
+	c     *LiveCollection[K, T]
+	items map[K]T
+	subs  subscribers[Change[T]]
+	close func()
+}
+
+// LiveWhere 注册实时过滤：结果为满足 predicate 的记录，订阅者收到结果集合的变更
+// （记录修改后开始或不再满足条件时分别为新增、删除）
+func LiveWhere[K comparable, T comparable](c *LiveCollection[K, T], predicate func(T) bool) *LiveSet[K, T] {
+	s := &LiveSet[K, T]{c: c, items: map[K]T{}}
+	s.close = c.attach(func(item T) {
+		if predicate(item) {
+			s.items[c.key(item)] = item
+		}
+	}, func(ch Change[T]) {
+		oldIn := ch.Kind != ChangeAdded && predicate(ch.Old)
+		newIn := ch.Kind != ChangeRemoved && predicate(ch.New)
+		switch {
+		case oldIn && newIn:
+			s.items[c.key(ch.New)] = ch.New
+			s.subs.notify(ch)
+		case oldIn:
+			delete(s.items, c.key(ch.Old))
+			s.subs.notify(Change[T]{Kind: ChangeRemoved, Old: ch.Old})
+		case newIn:
+			s.items[c.key(ch.New)] = ch.New
+			s.subs.notify(Change[T]{Kind: ChangeAdded, New: ch.New})
+		}
+	})
+	return s
+}
+
+// Len 返回结果中的记录个数
+func (s *LiveSet[K, T]) Len() int {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	return len(s.items)
+}
+
+// Query 返回当前结果的快照查询，顺序不固定
+func (s *LiveSet[K, T]) Query() Query[T] {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	return From(slices.Collect(maps.Values(s.items)))
+}
+
+// Subscribe 订阅结果变更，返回取消订阅的函数
+func (s *LiveSet[K, T]) Subscribe(fn func(Change[T])) func() {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	return s.subs.add(&s.c.mu, fn)
+}
+
+// Close 停止增量维护
+func (s *LiveSet[K, T]) Close() { s.close() }
+
+// aggregator 可增量添加与移除记录的聚合
+type aggregator[T, V any] interface {
+	add(item T)
+	remove(item T)
+	value() (V, bool)
+}
+
+type countAgg[T any] struct {
+	predicate func(T) bool
+	n         int
+}
+
+func (a *countAgg[T]) add(item T) {
+	if a.predicate(item) {
+		a.n++
+	}
+}
+
+func (a *countAgg[T]) remove(item T) {
+	if a.predicate(item) {
+		a.n--
+	}
+}
+
+func (a *countAgg[T]) value() (int, bool) { return a.n, true }
+
+type sumAgg[T any, V Integer | Float] struct {
+	selector func(T) V
+	sum      V
+}
+
+func (a *sumAgg[T, V]) add(item T)       { a.sum += a.selector(item) }
+func (a *sumAgg[T, V]) remove(item T)    { a.sum -= a.selector(item) }
+func (a *sumAgg[T, V]) value() (V, bool) { return a.sum, true }
+
+// extremumAgg 最值：按值有序保存各值的出现次数，移除当前最值后无需重新遍历
+type extremumAgg[T any, V cmp.Ordered] struct {
+	selector func(T) V
+	max      bool
+	values   []V
+	counts   []int
+}
+
+func (a *extremumAgg[T, V]) add(item T) {
+	v := a.selector(item)
+	i, found := slices.BinarySearch(a.values, v)
+	if found {
+		a.counts[i]++
+		return
+	}
+	a.values = slices.Insert(a.values, i, v)
+	a.counts = slices.Insert(a.counts, i, 1)
+}
+
+func (a *extremumAgg[T, V]) remove(item T) {
+	i, found := slices.BinarySearch(a.values, a.selector(item))
+	if !found {
+		return
+	}
+	if a.counts[i]--; a.counts[i] == 0 {
+		a.values = slices.Delete(a.values, i, i+1)
+		a.counts = slices.Delete(a.counts, i, i+1)
+	}
+}
+
+func (a *extremumAgg[T, V]) value() (V, bool) {
+	if len(a.values) == 0 {
+		var zero V
+		return zero, false
+	}
+	if a.max {
+		return a.values[len(a.values)-1], true
+	}
+	return a.values[0], true
+}
+
+// LiveValue 实时聚合值
+type LiveValue[V comparable] struct {
+	mu    *sync.Mutex
+	agg   interface{ value() (V, bool) }
+	subs  subscribers[Delta[V]]
+	close func()
+}
+
+func newLiveValue[K comparable, T comparable, V comparable](c *LiveCollection[K, T], agg aggregator[T, V]) *LiveValue[V] {
+	lv := &LiveValue[V]{mu: &c.mu, agg: agg}
+	lv.close = c.attach(agg.add, func(ch Change[T]) {
+		old, oldOK := agg.value()
+		if ch.Kind != ChangeAdded {
+			agg.remove(ch.Old)
+		}
+		if ch.Kind != ChangeRemoved {
+			agg.add(ch.New)
+		}
+		if v, ok := agg.value(); v != old || ok != oldOK {
+			lv.subs.notify(Delta[V]{Old: old, New: v})
+		}
+	})
+	return lv
+}
+
+// LiveCount 注册实时计数：满足 predicate 的记录个数
+func LiveCount[K comparable, T comparable](c *LiveCollection[K, T], predicate func(T) bool) *LiveValue[int] {
+	return newLiveValue(c, &countAgg[T]{predicate: predicate})
+}
+
+// LiveSum 注册实时求和；浮点数多次增减后可能有舍入误差
+func LiveSum[K comparable, T comparable, V Integer | Float](c *LiveCollection[K, T], selector func(T) V) *LiveValue[V] {
+	return newLiveValue(c, &sumAgg[T, V]{selector: selector})
+}
+
+// LiveMin 注册实时最小值
+func LiveMin[K comparable, T comparable, V cmp.Ordered](c *LiveCollection[K, T], selector func(T) V) *LiveValue[V] {
+	return newLiveValue(c, &extremumAgg[T, V]{selector: selector})
+}
+
+// LiveMax 注册实时最大值
+func LiveMax[K comparable, T comparable, V cmp.Ordered](c *LiveCollection[K, T], selector func(T) V) *LiveValue[V] {
+	return newLiveValue(c, &extremumAgg[T, V]{selector: selector, max: true})
+}
+
+// Value 返回当前值；LiveMin、LiveMax 在没有记录时返回零值与 false
+func (lv *LiveValue[V]) Value() (V, bool) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return lv.agg.value()
+}
+
+// Subscribe 订阅值的变化，返回取消订阅的函数；值不变的变更不通知
+func (lv *LiveValue[V]) Subscribe(fn func(Delta[V])) func() {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return lv.subs.add(lv.mu, fn)
+}
+
+// Close 停止增量维护
+func (lv *LiveValue[V]) Close() { lv.close() }
+
+// liveGroup 单个分组的聚合及记录个数
+type liveGroup[T, V any] struct {
+	agg   aggregator[T, V]
+	count int
+}
+
+// LiveGroups 实时分组统计
+type LiveGroups[G comparable, V comparable] struct {
+	mu     *sync.Mutex
+	values map[G]V
+	subs   subscribers[GroupDelta[G, V]]
+	close  func()
+}
+
+func newLiveGroups[K comparable, T comparable, G comparable, V comparable](c *LiveCollection[K, T], groupKey func(T) G, newAgg func() aggregator[T, V]) *LiveGroups[G, V] {
+	lg := &LiveGroups[G, V]{mu: &c.mu, values: map[G]V{}}
+	groups := map[G]*liveGroup[T, V]{}
+	// move 将 item 加入或移出分组 key，分组为空时删除
+	move := func(key G, item T, add bool) {
+		g, ok := groups[key]
+		if !ok {
+			g = &liveGroup[T, V]{agg: newAgg()}
+			groups[key] = g
+		}
+		if add {
+			g.agg.add(item)
+			g.count++
+		} else {
+			g.agg.remove(item)
+			g.count--
+		}
+		if g.count == 0 {
+			delete(groups, key)
+			delete(lg.values, key)
+			return
+		}
+		lg.values[key], _ = g.agg.value()
+	}
+	lg.close = c.attach(func(item T) { move(groupKey(item), item, true) }, func(ch Change[T]) {
+		// 受影响的分组：原分组在前，分组内修改只通知一次净变化
+		var keys []G
+		if ch.Kind != ChangeAdded {
+			keys = append(keys, groupKey(ch.Old))
+		}
+		if ch.Kind != ChangeRemoved {
+			if k := groupKey(ch.New); len(keys) == 0 || keys[0] != k {
+				keys = append(keys, k)
+			}
+		}
+		deltas := make([]GroupDelta[G, V], len(keys))
+		existed := make([]bool, len(keys))
+		for i, k := range keys {
+			deltas[i].Key = k
+			deltas[i].Old, existed[i] = lg.values[k]
+		}
+		if ch.Kind != ChangeAdded {
+			move(keys[0], ch.Old, false)
+		}
+		if ch.Kind != ChangeRemoved {
+			move(keys[len(keys)-1], ch.New, true)
+		}
+		for i, d := range deltas {
+			var exists bool
+			d.New, exists = lg.values[d.Key]
+			switch {
+			case !existed[i] && exists:
+				d.Kind = ChangeAdded
+			case existed[i] && !exists:
+				d.Kind = ChangeRemoved
+			case exists && d.Old != d.New:
+				d.Kind = ChangeUpdated
+			default:
+				continue
+			}
+			lg.subs.notify(d)
+		}
+	})
+	return lg
+}
+
+// LiveGroupCount 注册实时分组计数
+func LiveGroupCount[K comparable, T comparable, G comparable](c *LiveCollection[K, T], groupKey func(T) G) *LiveGroups[G, int] {
+	return newLiveGroups(c, groupKey, func() aggregator[T, int] {
+		return &countAgg[T]{predicate: func(T) bool { return true }}
+	})
+}
+
+// LiveGroupSum 注册实时分组求和
+func LiveGroupSum[K comparable, T comparable, G comparable, V Integer | Float](c *LiveCollection[K, T], groupKey func(T) G, selector func(T) V) *LiveGroups[G, V] {
+	return newLiveGroups(c, groupKey, func() aggregator[T, V] {
+		return &sumAgg[T, V]{selector: selector}
+	})
+}
+
+// Get 返回分组的当前值
+func (lg *LiveGroups[G, V]) Get(key G) (V, bool) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	v, ok := lg.values[key]
+	return v, ok
+}
+
+// Map 返回全部分组当前值的副本
+func (lg *LiveGroups[G, V]) Map() map[G]V {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	return maps.Clone(lg.values)
+}
+
+// Subscribe 订阅分组的变化，返回取消订阅的函数；记录在分组间移动时依次通知原分组与新分组
+func (lg *LiveGroups[G, V]) Subscribe(fn func(GroupDelta[G, V])) func() {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	return lg.subs.add(lg.mu, fn)
+}
+
+// Close 停止增量维护
+func (lg *LiveGroups[G, V]) Close() { lg.close() }
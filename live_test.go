@@ -0,0 +1,259 @@
+package linq
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// 测试记录编码为 id*1000+score，键为 id
+func liveRec(id, score int) int { return id*1000 + score }
+func liveID(r int) int          { return r / 1000 }
+func liveScore(r int) int       { return r % 1000 }
+
+func newLiveTestCollection(items ...int) *LiveCollection[int, int] {
+	return NewLiveCollection(liveID, items...)
+}
+
+func TestLiveCollectionBasic(t *testing.T) {
+	c := newLiveTestCollection(liveRec(1, 10), liveRec(2, 20))
+	var events []Change[int]
+	unsubscribe := c.Subscribe(func(ch Change[int]) { events = append(events, ch) })
+
+	if !c.Add(liveRec(3, 30)) {
+		t.Error("期望添加新键成功")
+	}
+	if c.Add(liveRec(3, 31)) {
+		t.Error("期望添加已存在的键失败")
+	}
+	if !c.Update(liveRec(1, 11)) {
+		t.Error("期望修改已存在的键成功")
+	}
+	if c.Update(liveRec(9, 1)) {
+		t.Error("期望修改不存在的键失败")
+	}
+	if !c.Update(liveRec(1, 11)) {
+		t.Error("期望修改为相同记录返回 true")
+	}
+	if old, ok := c.Remove(2); !ok || old != liveRec(2, 20) {
+		t.Errorf("期望删除返回 %d，实际得到 %d, %v", liveRec(2, 20), old, ok)
+	}
+	if _, ok := c.Remove(2); ok {
+		t.Error("期望重复删除失败")
+	}
+
+	want := []Change[int]{
+		{Kind: ChangeAdded, New: liveRec(3, 30)},
+		{Kind: ChangeUpdated, Old: liveRec(1, 10), New: liveRec(1, 11)},
+		{Kind: ChangeRemoved, Old: liveRec(2, 20)},
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("期望事件 %v，实际得到 %v", want, events)
+	}
+
+	unsubscribe()
+	c.Add(liveRec(4, 40))
+	if len(events) != len(want) {
+		t.Errorf("期望取消订阅后不再收到事件，实际得到 %v", events[len(want):])
+	}
+
+	got := OrderBy(c.Query(), func(r int) int { return r }).ToSlice()
+	if !slices.Equal(got, []int{liveRec(1, 11), liveRec(3, 30), liveRec(4, 40)}) {
+		t.Errorf("期望快照 [1011 3030 4040]，实际得到 %v", got)
+	}
+	if r, ok := c.Get(3); !ok || r != liveRec(3, 30) || c.Len() != 3 {
+		t.Errorf("期望 Get(3)=3030 且 Len=3，实际得到 %d, %v, %d", r, ok, c.Len())
+	}
+}
+
+func TestLiveWhere(t *testing.T) {
+	c := newLiveTestCollection(liveRec(1, 10), liveRec(2, 60), liveRec(3, 70))
+	passed := LiveWhere(c, func(r int) bool { return liveScore(r) >= 60 })
+	if passed.Len() != 2 {
+		t.Fatalf("期望初始结果 2 条，实际得到 %d", passed.Len())
+	}
+	var events []Change[int]
+	passed.Subscribe(func(ch Change[int]) { events = append(events, ch) })
+
+	c.Update(liveRec(1, 65)) // 进入结果
+	c.Update(liveRec(2, 50)) // 离开结果
+	c.Update(liveRec(3, 80)) // 结果内修改
+	c.Add(liveRec(4, 5))     // 不满足条件
+	c.Remove(4)
+	c.Remove(3)
+
+	want := []Change[int]{
+		{Kind: ChangeAdded, New: liveRec(1, 65)},
+		{Kind: ChangeRemoved, Old: liveRec(2, 60)},
+		{Kind: ChangeUpdated, Old: liveRec(3, 70), New: liveRec(3, 80)},
+		{Kind: ChangeRemoved, Old: liveRec(3, 80)},
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("期望事件 %v，实际得到 %v", want, events)
+	}
+	if got := passed.Query().ToSlice(); !slices.Equal(got, []int{liveRec(1, 65)}) {
+		t.Errorf("期望结果 [1065]，实际得到 %v", got)
+	}
+
+	passed.Close()
+	c.Update(liveRec(2, 90))
+	if passed.Len() != 1 || len(events) != len(want) {
+		t.Error("期望 Close 后不再维护结果")
+	}
+}
+
+func TestLiveAggregates(t *testing.T) {
+	c := newLiveTestCollection(liveRec(1, 10), liveRec(2, 30), liveRec(3, 30))
+	count := LiveCount(c, func(r int) bool { return liveScore(r) >= 30 })
+	sum := LiveSum(c, liveScore)
+	minScore := LiveMin(c, liveScore)
+	maxScore := LiveMax(c, liveScore)
+
+	var sums []Delta[int]
+	sum.Subscribe(func(d Delta[int]) { sums = append(sums, d) })
+	var maxes []Delta[int]
+	maxScore.Subscribe(func(d Delta[int]) { maxes = append(maxes, d) })
+
+	check := func(step string, wantCount, wantSum, wantMin, wantMax int) {
+		t.Helper()
+		n, _ := count.Value()
+		s, _ := sum.Value()
+		lo, _ := minScore.Value()
+		hi, _ := maxScore.Value()
+		if n != wantCount || s != wantSum || lo != wantMin || hi != wantMax {
+			t.Errorf("%s：期望 count=%d sum=%d min=%d max=%d，实际得到 %d %d %d %d",
+				step, wantCount, wantSum, wantMin, wantMax, n, s, lo, hi)
+		}
+	}
+	check("初始", 2, 70, 10, 30)
+	c.Remove(2) // 30 仍有另一条记录
+	check("删除重复最大值", 1, 40, 10, 30)
+	c.Remove(3)
+	check("删除最大值", 0, 10, 10, 10)
+	c.Add(liveRec(4, 50))
+	check("添加", 1, 60, 10, 50)
+	c.Update(liveRec(1, 20))
+	check("修改最小值", 1, 70, 20, 50)
+
+	wantSums := []Delta[int]{{70, 40}, {40, 10}, {10, 60}, {60, 70}}
+	if !slices.Equal(sums, wantSums) {
+		t.Errorf("期望求和变化 %v，实际得到 %v", wantSums, sums)
+	}
+	wantMaxes := []Delta[int]{{30, 10}, {10, 50}}
+	if !slices.Equal(maxes, wantMaxes) {
+		t.Errorf("期望最大值变化 %v，实际得到 %v（值不变时不通知）", wantMaxes, maxes)
+	}
+
+	c.Remove(1)
+	c.Remove(4)
+	if v, ok := minScore.Value(); ok || v != 0 {
+		t.Errorf("期望空集合的最小值为 0, false，实际得到 %d, %v", v, ok)
+	}
+}
+
+func TestLiveGroups(t *testing.T) {
+	// 分组键为 score 的十位，求和按 score
+	group := func(r int) int { return liveScore(r) / 10 }
+	c := newLiveTestCollection(liveRec(1, 11), liveRec(2, 12), liveRec(3, 25))
+	counts := LiveGroupCount(c, group)
+	sums := LiveGroupSum(c, group, liveScore)
+
+	if got := counts.Map(); len(got) != 2 || got[1] != 2 || got[2] != 1 {
+		t.Fatalf("期望初始分组计数 map[1:2 2:1]，实际得到 %v", got)
+	}
+	var events []GroupDelta[int, int]
+	counts.Subscribe(func(d GroupDelta[int, int]) { events = append(events, d) })
+
+	c.Update(liveRec(3, 31)) // 从分组 2 移到新分组 3
+	c.Update(liveRec(1, 15)) // 分组内修改，计数不变
+	c.Remove(2)
+
+	want := []GroupDelta[int, int]{
+		{Kind: ChangeRemoved, Key: 2, Old: 1},
+		{Kind: ChangeAdded, Key: 3, New: 1},
+		{Kind: ChangeUpdated, Key: 1, Old: 2, New: 1},
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("期望分组变化 %v，实际得到 %v", want, events)
+	}
+	if _, ok := counts.Get(2); ok {
+		t.Error("期望空分组被删除")
+	}
+	if got := sums.Map(); len(got) != 2 || got[1] != 15 || got[3] != 31 {
+		t.Errorf("期望分组求和 map[1:15 3:31]，实际得到 %v", got)
+	}
+}
+
+// 随机变更后增量结果应与全量重新计算一致
+func TestLiveMatchesRecompute(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	c := newLiveTestCollection()
+	even := func(r int) bool { return liveScore(r)%2 == 0 }
+	group := func(r int) int { return liveScore(r) % 7 }
+	set := LiveWhere(c, even)
+	sum := LiveSum(c, liveScore)
+	hi := LiveMax(c, liveScore)
+	groups := LiveGroupSum(c, group, liveScore)
+
+	for range 2000 {
+		id, score := rng.IntN(50), rng.IntN(1000)
+		switch rng.IntN(3) {
+		case 0:
+			c.Add(liveRec(id, score))
+		case 1:
+			c.Update(liveRec(id, score))
+		default:
+			c.Remove(id)
+		}
+	}
+
+	all := c.Query().ToSlice()
+	if got, want := set.Len(), From(all).Where(even).Count(); got != want {
+		t.Errorf("期望过滤结果 %d 条，实际得到 %d", want, got)
+	}
+	wantSum := 0
+	wantGroups := map[int]int{}
+	for _, r := range all {
+		wantSum += liveScore(r)
+		wantGroups[group(r)] += liveScore(r)
+	}
+	if got, _ := sum.Value(); got != wantSum {
+		t.Errorf("期望求和 %d，实际得到 %d", wantSum, got)
+	}
+	if len(all) > 0 {
+		want := slices.Max(Select(From(all), liveScore).ToSlice())
+		if got, _ := hi.Value(); got != want {
+			t.Errorf("期望最大值 %d，实际得到 %d", want, got)
+		}
+	}
+	got := groups.Map()
+	if len(got) != len(wantGroups) {
+		t.Errorf("期望 %d 个分组，实际得到 %d", len(wantGroups), len(got))
+	}
+	for k, v := range wantGroups {
+		if got[k] != v {
+			t.Errorf("分组 %d：期望 %d，实际得到 %d", k, v, got[k])
+		}
+	}
+}
+
+func TestLiveConcurrent(t *testing.T) {
+	c := newLiveTestCollection()
+	count := LiveCount(c, func(int) bool { return true })
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				c.Add(liveRec(w*100+i, 1))
+				count.Value()
+			}
+		}()
+	}
+	wg.Wait()
+	if n, _ := count.Value(); n != 400 {
+		t.Errorf("期望计数 400，实际得到 %d", n)
+	}
+}
@@ -0,0 +1,111 @@
+package linq
+
+import (
+	"cmp"
+)
+
+// NullOrder 指定排序时 nil 键的位置，与升降序无关
+type NullOrder uint8
+
+const (
+	NullsLast  NullOrder = iota // nil 排在最后（默认）
+	NullsFirst                  // nil 排在最前
+)
+
+// AscPtr 根据指针键选择器生成升序比较器，非 nil 键按指向的值比较，nil 键的位置由 nulls 指定（默认 NullsLast）
+func AscPtr[T comparable, K cmp.Ordered](selector func(T) *K, nulls ...NullOrder) CompareFunc[T] {
+	return AscPtrFunc(selector, cmp.Compare[K], nulls...)
+}
+
+// DescPtr 根据指针键选择器生成降序比较器，nil 键的位置由 nulls 指定（默认 NullsLast）
+func DescPtr[T comparable, K cmp.Ordered](selector func(T) *K, nulls ...NullOrder) CompareFunc[T] {
+	return DescPtrFunc(selector, cmp.Compare[K], nulls...)
+}
+
+// AscPtrFunc 使用自定义比较函数的指针键升序比较器，如 AscPtrFunc(selector, time.Time.Compare)
+func AscPtrFunc[T comparable, K any](selector func(T) *K, compare func(a, b K) int, nulls ...NullOrder) CompareFunc[T] {
+	nullsFirst := len(nulls) > 0 && nulls[0] == NullsFirst
+	return func(a, b T) int {
+		return comparePtr(selector(a), selector(b), compare, nullsFirst)
+	}
+}
+
+// DescPtrFunc 使用自定义比较函数的指针键降序比较器
+func DescPtrFunc[T comparable, K any](selector func(T) *K, compare func(a, b K) int, nulls ...NullOrder) CompareFunc[T] {
+	return AscPtrFunc(selector, func(a, b K) int { return compare(b, a) }, nulls...)
+}
+
+func comparePtr[K any](a, b *K, compare func(a, b K) int, nullsFirst bool) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		if nullsFirst {
+			return -1
+		}
+		return 1
+	case b == nil:
+		if nullsFirst {
+			return 1
+		}
+		return -1
+	}
+	return compare(*a, *b)
+}
+
+// MinByPtr 返回指针键最小的元素，跳过键为 nil 的元素；全部为 nil 或序列为空时 ok 为 false
+func MinByPtr[T comparable, R cmp.Ordered](q Query[T], selector func(T) *R) (result T, ok bool) {
+	var minR R
+	for item := range q.Seq() {
+		val := selector(item)
+		if val == nil {
+			continue
+		}
+		if !ok || cmp.Compare(*val, minR) < 0 {
+			result, minR, ok = item, *val, true
+		}
+	}
+	return result, ok
+}
+
+// MaxByPtr 返回指针键最大的元素，跳过键为 nil 的元素；全部为 nil 或序列为空时 ok 为 false
+func MaxByPtr[T comparable, R cmp.Ordered](q Query[T], selector func(T) *R) (result T, ok bool) {
+	var maxR R
+	for item := range q.Seq() {
+		val := selector(item)
+		if val == nil {
+			continue
+		}
+		if !ok || cmp.Compare(*val, maxR) > 0 {
+			result, maxR, ok = item, *val, true
+		}
+	}
+	return result, ok
+}
+
+// SumByPtr 对非 nil 的指针键求和；全部为 nil 或序列为空时 ok 为 false（对应 SQL 的 NULL）
+func SumByPtr[T comparable, R Integer | Float | Complex](q Query[T], selector func(T) *R) (sum R, ok bool) {
+	for item := range q.Seq() {
+		if val := selector(item); val != nil {
+			sum += *val
+			ok = true
+		}
+	}
+	return sum, ok
+}
+
+// AverageByPtr 对非 nil 的指针键求平均值，nil 不计入个数；全部为 nil 或序列为空时 ok 为 false
+func AverageByPtr[T comparable, R Integer | Float](q Query[T], selector func(T) *R) (float64, bool) {
+	var sum float64
+	count := 0
+	for item := range q.Seq() {
+		if val := selector(item); val != nil {
+			sum += float64(*val)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
@@ -0,0 +1,94 @@
+package linq
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+type nullableMember struct {
+	Name     string
+	Age      *int
+	JoinedAt *time.Time
+}
+
+func nullableMembers() []*nullableMember {
+	age := func(v int) *int { return &v }
+	day := func(d int) *time.Time {
+		t := time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	return []*nullableMember{
+		{"a", age(30), day(3)},
+		{"b", nil, day(1)},
+		{"c", age(20), nil},
+		{"d", nil, nil},
+		{"e", age(25), day(2)},
+	}
+}
+
+func memberNames(q Query[*nullableMember]) []string {
+	return Select(q, func(m *nullableMember) string { return m.Name }).ToSlice()
+}
+
+// TestPtrComparators 测试指针键比较器及 nil 位置
+func TestPtrComparators(t *testing.T) {
+	q := From(nullableMembers())
+	byAge := func(m *nullableMember) *int { return m.Age }
+
+	cases := []struct {
+		name string
+		cmp  CompareFunc[*nullableMember]
+		want []string
+	}{
+		{"AscPtr", AscPtr(byAge), []string{"c", "e", "a", "b", "d"}},
+		{"AscPtr NullsFirst", AscPtr(byAge, NullsFirst), []string{"b", "d", "c", "e", "a"}},
+		{"DescPtr", DescPtr(byAge), []string{"a", "e", "c", "b", "d"}},
+		{"DescPtr NullsFirst", DescPtr(byAge, NullsFirst), []string{"b", "d", "a", "e", "c"}},
+		{"AscPtrFunc", AscPtrFunc(func(m *nullableMember) *time.Time { return m.JoinedAt }, time.Time.Compare), []string{"b", "e", "a", "c", "d"}},
+		{"DescPtrFunc", DescPtrFunc(func(m *nullableMember) *time.Time { return m.JoinedAt }, time.Time.Compare, NullsFirst), []string{"c", "d", "a", "e", "b"}},
+	}
+	for _, c := range cases {
+		if got := memberNames(q.Order(c.cmp).Query); !slices.Equal(got, c.want) {
+			t.Errorf("%s 期望 %v，实际得到 %v", c.name, c.want, got)
+		}
+	}
+	// 与 Then 组合：年龄为空时按名称降序
+	got := memberNames(q.Order(AscPtr(byAge)).Then(Desc(func(m *nullableMember) string { return m.Name })).Query)
+	if !slices.Equal(got, []string{"c", "e", "a", "d", "b"}) {
+		t.Errorf("Then 组合错误: %v", got)
+	}
+}
+
+// TestPtrAggregates 测试跳过 nil 的聚合
+func TestPtrAggregates(t *testing.T) {
+	q := From(nullableMembers())
+	byAge := func(m *nullableMember) *int { return m.Age }
+
+	if m, ok := MinByPtr(q, byAge); !ok || m.Name != "c" {
+		t.Errorf("MinByPtr 期望 c，实际得到 %v %v", m, ok)
+	}
+	if m, ok := MaxByPtr(q.Where(func(m *nullableMember) bool { return m.Name != "a" }), byAge); !ok || m.Name != "e" {
+		t.Errorf("MaxByPtr 期望 e，实际得到 %v %v", m, ok)
+	}
+	if sum, ok := SumByPtr(q, byAge); !ok || sum != 75 {
+		t.Errorf("SumByPtr 期望 75，实际得到 %d %v", sum, ok)
+	}
+	if avg, ok := AverageByPtr(q, byAge); !ok || avg != 25 {
+		t.Errorf("AverageByPtr 期望 25（nil 不计入个数），实际得到 %v %v", avg, ok)
+	}
+
+	nulls := q.Where(func(m *nullableMember) bool { return m.Age == nil })
+	if _, ok := MinByPtr(nulls, byAge); ok {
+		t.Errorf("全部为 nil 时 MinByPtr 应返回 false")
+	}
+	if _, ok := MaxByPtr(QueryEmpty[*nullableMember](), byAge); ok {
+		t.Errorf("空序列 MaxByPtr 应返回 false")
+	}
+	if sum, ok := SumByPtr(nulls, byAge); ok || sum != 0 {
+		t.Errorf("全部为 nil 时 SumByPtr 应返回 (0, false)，实际得到 (%d, %v)", sum, ok)
+	}
+	if _, ok := AverageByPtr(nulls, byAge); ok {
+		t.Errorf("全部为 nil 时 AverageByPtr 应返回 false")
+	}
+}
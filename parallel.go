@@ -0,0 +1,447 @@
+package linq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+)
+
+// 带选项的并发处理：回调返回 error，支持限速（令牌桶）、单次尝试超时、指数退避重试及失败处理方式。
+// 时间相关操作均通过 Clock 完成，测试中可替换为可控时钟。
+
+// FailMode 元素处理失败（重试用尽）后的处理方式
+type FailMode uint8
+
+const (
+	FailFast      FailMode = iota // 首个失败即取消其余处理并返回该错误（默认）
+	CollectErrors                 // 继续处理，返回所有失败合并后的错误（errors.Join）
+	SkipErrors                    // 忽略失败的元素，不返回错误
+)
+
+// Clock 时间源
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// SystemClock 返回基于 time 包的系统时钟
+func SystemClock() Clock { return systemClock{} }
+
+// RetryPolicy 重试策略：第 n 次重试前等待 BaseDelay*Multiplier^(n-1)，不超过 MaxDelay，并按 Jitter 比例随机缩短
+type RetryPolicy struct {
+	MaxAttempts int              // 最多尝试次数（含首次），不大于 1 时不重试
+	BaseDelay   time.Duration    // 首次重试前的等待时间
+	MaxDelay    time.Duration    // 等待时间上限，0 表示不限
+	Multiplier  float64          // 退避倍数，小于 1 时为 2
+	Jitter      float64          // 随机抖动比例 [0, 1]，等待时间在 [d*(1-Jitter), d] 内均匀分布
+	Retryable   func(error) bool // 判断错误是否可重试，nil 时除 Permanent 包装的错误外均重试
+}
+
+// delay 第 attempt 次尝试失败后的等待时间
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	mult := p.Multiplier
+	if mult < 1 {
+		mult = 2
+	}
+	d := float64(p.BaseDelay) * math.Pow(mult, float64(attempt-1))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 {
+		d *= 1 - jitter*rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	var perm *permanentError
+	return !errors.As(err, &perm)
+}
+
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 将错误标记为不可重试（默认重试判断下）
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// ParallelOptions 并发处理选项，零值表示单个 worker、不限速、无超时、不重试、FailFast
+type ParallelOptions struct {
+	Workers   int           // worker 数量，默认 1
+	RateLimit float64       // 每秒最多开始的尝试次数（含重试），0 表示不限
+	Burst     int           // 令牌桶容量，默认 1
+	Timeout   time.Duration // 单次尝试的超时，回调的 ctx 到期后返回 context.DeadlineExceeded
+	Retry     RetryPolicy
+	FailMode  FailMode
+	Clock     Clock // 默认 SystemClock()
+	Pool      *Pool // 指定时由共享的 Pool 执行，Workers 为本次调用占用的并发上限（0 表示仅受 Pool 限制）；默认使用 WithPool 绑定的 Pool
+	// RecoverPanics 为 true 时回调的 panic 转为 *PanicError（不重试），按 FailMode 与其他失败一同处理；
+	// 默认在调用方重新抛出首个 panic
+	RecoverPanics bool
+}
+
+// ItemError 单个元素处理失败
+type ItemError struct {
+	Index    int // 元素在序列中的位置
+	Item     any
+	Worker   int // 执行该元素的 worker 序号
+	Attempts int // 已尝试次数
+	Err      error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("linq: item %d (%v) failed on worker %d after %d attempt(s): %v", e.Index, e.Item, e.Worker, e.Attempts, e.Err)
+}
+
+func (e *ItemError) Unwrap() error { return e.Err }
+
+// PanicError 回调中恢复的 panic 及其调用栈
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("linq: panic: %v", e.Value) }
+
+// Unwrap panic 值为 error 时返回该值
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// ForEachParallelOpts 按选项并发执行 action（不保证顺序）；ctx 为 nil 时使用 WithContext 绑定的上下文。
+// 返回值取决于 FailMode，ctx 取消时返回 ctx.Err()
+func (q Query[T]) ForEachParallelOpts(ctx context.Context, action func(context.Context, T) error, opts ParallelOptions) error {
+	return runParallel(ctx, q, opts, func(ctx context.Context, _ int, item T) error {
+		return action(ctx, item)
+	})
+}
+
+// SelectAsyncOpts 按选项并发转换元素，结果保持输入顺序；CollectErrors 与 SkipErrors 下失败的元素不出现在结果中。
+// ctx 为 nil 时使用 WithContext 绑定的上下文
+func SelectAsyncOpts[T, V comparable](ctx context.Context, q Query[T], selector func(context.Context, T) (V, error), opts ParallelOptions) ([]V, error) {
+	var mu sync.Mutex
+	var pairs []KV[int, V]
+	err := runParallel(ctx, q, opts, func(ctx context.Context, index int, item T) error {
+		v, err := selector(ctx, item)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		pairs = append(pairs, KV[int, V]{index, v})
+		mu.Unlock()
+		return nil
+	})
+	if err != nil && opts.FailMode == FailFast {
+		return nil, err
+	}
+	slices.SortFunc(pairs, func(a, b KV[int, V]) int { return a.Key - b.Key })
+	result := make([]V, len(pairs))
+	for i, p := range pairs {
+		result[i] = p.Value
+	}
+	return result, err
+}
+
+// ForEachParallelErr 并发执行 action（不保证顺序），不在调用方重新抛出 panic：
+// 所有 panic 汇总为按元素顺序合并（errors.Join）的 *ItemError，其 Err 为含调用栈的 *PanicError；ctx 取消时一并返回 ctx.Err()
+func (q Query[T]) ForEachParallelErr(ctx context.Context, action func(T), workers ...int) error {
+	return runParallel(ctx, q, collectPanics(workers), func(_ context.Context, _ int, item T) error {
+		action(item)
+		return nil
+	})
+}
+
+// SelectAsyncErr 并发转换元素，结果保持输入顺序且不含 panic 的元素；panic 的汇总方式同 ForEachParallelErr
+func SelectAsyncErr[T, V comparable](ctx context.Context, q Query[T], selector func(T) V, workers ...int) ([]V, error) {
+	return SelectAsyncOpts(ctx, q, func(_ context.Context, item T) (V, error) {
+		return selector(item), nil
+	}, collectPanics(workers))
+}
+
+// collectPanics 汇总全部 panic 的选项
+func collectPanics(workers []int) ParallelOptions {
+	opts := ParallelOptions{FailMode: CollectErrors, RecoverPanics: true}
+	if len(workers) > 0 {
+		opts.Workers = workers[0]
+	}
+	return opts
+}
+
+// runParallel 并发执行 fn，worker 中的 panic 在调用方重新抛出；指定 Pool 时由 Pool 的 worker 执行
+func runParallel[T comparable](ctx context.Context, q Query[T], opts ParallelOptions, fn func(context.Context, int, T) error) error {
+	if ctx == nil {
+		ctx = firstContext(q.ctx, context.Background())
+	}
+	if opts.Pool == nil {
+		opts.Pool = poolFrom(ctx)
+	}
+	r := newParallelRunner(opts)
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	panicCh := make(chan any, 1)
+	var (
+		mu       sync.Mutex
+		errs     []*ItemError
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+	// handle 处理单个元素，worker 为执行者序号
+	handle := func(index int, item T, worker int) {
+		defer func() {
+			if p := recover(); p != nil {
+				tracePanic(q.observer, worker, p)
+				select {
+				case panicCh <- p:
+				default:
+				}
+				cancel()
+			}
+		}()
+		if runCtx.Err() != nil {
+			return
+		}
+		attempts, err := r.process(runCtx, func(ctx context.Context) (err error) {
+			if opts.RecoverPanics {
+				defer func() {
+					if p := recover(); p != nil {
+						tracePanic(q.observer, worker, p)
+						err = &PanicError{Value: p, Stack: debug.Stack()}
+					}
+				}()
+			}
+			return fn(ctx, index, item)
+		})
+		if err == nil || runCtx.Err() != nil {
+			// 因取消导致的失败由调用方通过 ctx.Err() 获知
+			return
+		}
+		itemErr := &ItemError{Index: index, Item: item, Worker: worker, Attempts: attempts, Err: err}
+		switch opts.FailMode {
+		case FailFast:
+			fail(itemErr)
+		case CollectErrors:
+			mu.Lock()
+			errs = append(errs, itemErr)
+			mu.Unlock()
+		}
+	}
+
+	if opts.Pool != nil {
+		// Workers 限制本次调用占用 Pool 的并发数，0 表示仅受 Pool 限制
+		var slots chan struct{}
+		if opts.Workers > 0 {
+			slots = make(chan struct{}, opts.Workers)
+		}
+		index := 0
+		for item := range q.Seq() {
+			if slots != nil {
+				select {
+				case <-runCtx.Done():
+				case slots <- struct{}{}:
+				}
+			}
+			if runCtx.Err() != nil {
+				break
+			}
+			wg.Add(1)
+			i := index
+			err := opts.Pool.submit(runCtx, func(worker int) {
+				defer wg.Done()
+				if slots != nil {
+					defer func() { <-slots }()
+				}
+				handle(i, item, worker)
+			})
+			if err != nil {
+				wg.Done()
+				if errors.Is(err, ErrPoolClosed) {
+					fail(err)
+				}
+				break
+			}
+			index++
+		}
+	} else {
+		type job struct {
+			index int
+			item  T
+		}
+		jobs := make(chan job, r.workers)
+		for i := 0; i < r.workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					handle(j.index, j.item, i)
+				}
+			}()
+		}
+		emit := func(j job) bool {
+			select {
+			case <-runCtx.Done():
+				return false
+			case jobs <- j:
+				return true
+			}
+		}
+		index := 0
+		for item := range q.Seq() {
+			if !emit(job{index, item}) {
+				break
+			}
+			index++
+		}
+		close(jobs)
+	}
+	wg.Wait()
+
+	select {
+	case p := <-panicCh:
+		panic(p)
+	default:
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	slices.SortFunc(errs, func(a, b *ItemError) int { return a.Index - b.Index })
+	joined := make([]error, 0, len(errs)+1)
+	for _, e := range errs {
+		joined = append(joined, e)
+	}
+	joined = append(joined, ctx.Err())
+	return errors.Join(joined...)
+}
+
+// parallelRunner 单个元素的限速、超时与重试
+type parallelRunner struct {
+	workers int
+	timeout time.Duration
+	retry   RetryPolicy
+	clock   Clock
+	limiter *tokenBucket
+}
+
+func newParallelRunner(opts ParallelOptions) *parallelRunner {
+	r := &parallelRunner{workers: max(opts.Workers, 1), timeout: opts.Timeout, retry: opts.Retry, clock: opts.Clock}
+	if r.clock == nil {
+		r.clock = systemClock{}
+	}
+	if opts.RateLimit > 0 {
+		r.limiter = newTokenBucket(r.clock, opts.RateLimit, max(opts.Burst, 1))
+	}
+	return r
+}
+
+// process 执行一次或多次尝试，返回尝试次数及最后的错误
+func (r *parallelRunner) process(ctx context.Context, fn func(context.Context) error) (int, error) {
+	for attempt := 1; ; attempt++ {
+		if r.limiter != nil {
+			if err := r.limiter.wait(ctx); err != nil {
+				return attempt - 1, err
+			}
+		}
+		err := r.attempt(ctx, fn)
+		var panicErr *PanicError
+		if err == nil || attempt >= r.retry.MaxAttempts || errors.As(err, &panicErr) || !r.retry.retryable(err) {
+			return attempt, err
+		}
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-r.clock.After(r.retry.delay(attempt)):
+		}
+	}
+}
+
+// attempt 执行单次尝试，超时后回调的 ctx 被取消，返回 context.DeadlineExceeded
+func (r *parallelRunner) attempt(ctx context.Context, fn func(context.Context) error) error {
+	if r.timeout <= 0 {
+		return fn(ctx)
+	}
+	if _, ok := r.clock.(systemClock); ok {
+		attemptCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+		return fn(attemptCtx)
+	}
+	attemptCtx, cancel := context.WithCancelCause(ctx)
+	stop := r.clock.AfterFunc(r.timeout, func() { cancel(context.DeadlineExceeded) })
+	defer cancel(nil)
+	defer stop()
+	err := fn(attemptCtx)
+	if err != nil && errors.Is(err, context.Canceled) && context.Cause(attemptCtx) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// tokenBucket 令牌桶限速器
+type tokenBucket struct {
+	mu     sync.Mutex
+	clock  Clock
+	rate   float64 // 每秒补充的令牌数
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(clock Clock, rate float64, burst int) *tokenBucket {
+	return &tokenBucket{clock: clock, rate: rate, burst: float64(burst), tokens: float64(burst), last: clock.Now()}
+}
+
+// wait 取得一个令牌，ctx 取消时返回 ctx.Err()
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := b.clock.Now()
+		if elapsed := now.Sub(b.last); elapsed > 0 {
+			b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+			b.last = now
+		}
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		need := time.Duration(math.Ceil((1 - b.tokens) / b.rate * float64(time.Second)))
+		b.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.clock.After(need):
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package linq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock 可控时钟：After 立即将时间推进 d 并返回就绪的通道，AfterFunc 在时间推进到期后触发
+type fakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond // AfterFunc 注册定时器时通知 waitTimers
+	now    time.Time
+	timers []*fakeTimer
+	sleeps []time.Duration
+}
+
+type fakeTimer struct {
+	at   time.Time
+	f    func()
+	done bool
+}
+
+func newFakeClock() *fakeClock {
+	c := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.sleeps = append(c.sleeps, d)
+	c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	ch <- c.Advance(d)
+	return ch
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		stopped := !t.done
+		t.done = true
+		return stopped
+	}
+}
+
+// Advance 推进时间并触发到期的定时器，返回推进后的时间
+func (c *fakeClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	var due []func()
+	for _, t := range c.timers {
+		if !t.done && !t.at.After(now) {
+			t.done = true
+			due = append(due, t.f)
+		}
+	}
+	c.mu.Unlock()
+	for _, f := range due {
+		f()
+	}
+	return now
+}
+
+// waitTimers 等待累计注册 n 个定时器，用于在推进时间前确认被测代码已开始计时
+func (c *fakeClock) waitTimers(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *fakeClock) sleepLog() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.sleeps)
+}
+
+var errTransient = errors.New("transient")
+
+// TestParallelRetryBackoff 测试指数退避及上限
+func TestParallelRetryBackoff(t *testing.T) {
+	clock := newFakeClock()
+	var calls atomic.Int32
+	err := From([]int{1}).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+		if calls.Add(1) < 4 {
+			return errTransient
+		}
+		return nil
+	}, ParallelOptions{
+		Retry: RetryPolicy{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond},
+		Clock: clock,
+	})
+	if err != nil || calls.Load() != 4 {
+		t.Fatalf("期望第 4 次成功，实际调用 %d 次，错误 %v", calls.Load(), err)
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}
+	if got := clock.sleepLog(); !slices.Equal(got, want) {
+		t.Errorf("期望等待 %v，实际得到 %v", want, got)
+	}
+}
+
+// TestParallelRetryGiveUp 测试重试用尽及不可重试的错误
+func TestParallelRetryGiveUp(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	for _, tt := range []struct {
+		name     string
+		err      error
+		attempts int
+	}{
+		{"重试用尽", errTransient, 3},
+		{"Permanent", Permanent(errTransient), 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := From([]int{7}).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+				return tt.err
+			}, ParallelOptions{Retry: policy, Clock: newFakeClock()})
+			var itemErr *ItemError
+			if !errors.As(err, &itemErr) || !errors.Is(err, errTransient) {
+				t.Fatalf("期望包装 errTransient 的 ItemError，实际得到 %v", err)
+			}
+			if itemErr.Attempts != tt.attempts || itemErr.Item != 7 || itemErr.Index != 0 {
+				t.Errorf("期望第 0 个元素 7 尝试 %d 次，实际得到 %+v", tt.attempts, itemErr)
+			}
+		})
+	}
+
+	// 自定义可重试判断
+	calls := 0
+	From([]int{1}).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+		calls++
+		return errTransient
+	}, ParallelOptions{Retry: RetryPolicy{MaxAttempts: 5, Retryable: func(error) bool { return false }}, Clock: newFakeClock()})
+	if calls != 1 {
+		t.Errorf("Retryable 返回 false 时期望只尝试 1 次，实际 %d 次", calls)
+	}
+}
+
+// TestRetryJitter 抖动后的等待时间落在 [d*(1-Jitter), d] 内
+func TestRetryJitter(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, Multiplier: 3, Jitter: 0.5}
+	for range 100 {
+		if d := p.delay(2); d < 1500*time.Millisecond || d > 3*time.Second {
+			t.Fatalf("期望等待时间在 [1.5s, 3s] 内，实际得到 %v", d)
+		}
+	}
+}
+
+// TestParallelRateLimit 令牌桶限速：突发 2 个之后每 100ms 开始一次尝试
+func TestParallelRateLimit(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	var mu sync.Mutex
+	var starts []time.Duration
+	err := QueryRange(0, 6).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+		mu.Lock()
+		starts = append(starts, clock.Now().Sub(start))
+		mu.Unlock()
+		return nil
+	}, ParallelOptions{Workers: 3, RateLimit: 10, Burst: 2, Clock: clock})
+	if err != nil || len(starts) != 6 {
+		t.Fatalf("期望 6 次调用且无错误，实际 %d 次，错误 %v", len(starts), err)
+	}
+	slices.Sort(starts)
+	for i, s := range starts {
+		if earliest := time.Duration(max(i-1, 0)) * 100 * time.Millisecond; s < earliest {
+			t.Errorf("第 %d 次尝试期望不早于 %v，实际在 %v", i, earliest, s)
+		}
+	}
+}
+
+// TestParallelTimeout 超时的尝试收到 DeadlineExceeded 并可重试
+func TestParallelTimeout(t *testing.T) {
+	clock := newFakeClock()
+	var calls atomic.Int32
+	started := make(chan struct{})
+	go func() {
+		<-started
+		clock.Advance(time.Second)
+	}()
+	got, err := SelectAsyncOpts(context.Background(), From([]int{5}), func(ctx context.Context, i int) (int, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		return i * 2, nil
+	}, ParallelOptions{Timeout: time.Second, Retry: RetryPolicy{MaxAttempts: 2}, Clock: clock})
+	if err != nil || !slices.Equal(got, []int{10}) || calls.Load() != 2 {
+		t.Errorf("期望超时后重试成功得到 [10]，实际得到 %v、%v，调用 %d 次", got, err, calls.Load())
+	}
+
+	// 系统时钟
+	err = From([]int{1}).ForEachParallelOpts(context.Background(), func(ctx context.Context, _ int) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, ParallelOptions{Timeout: 10 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("期望 DeadlineExceeded，实际得到 %v", err)
+	}
+}
+
+// TestParallelFailModes 测试三种失败处理方式
+func TestParallelFailModes(t *testing.T) {
+	var processed atomic.Int32
+	oddOnly := func(_ context.Context, i int) (int, error) {
+		processed.Add(1)
+		if i%2 == 0 {
+			return 0, errTransient
+		}
+		return i * 10, nil
+	}
+	q := QueryRange(1, 10)
+
+	got, err := SelectAsyncOpts(context.Background(), q, oddOnly, ParallelOptions{FailMode: FailFast})
+	var itemErr *ItemError
+	if got != nil || !errors.As(err, &itemErr) || itemErr.Item != 2 {
+		t.Errorf("FailFast 期望 nil 与元素 2 的错误，实际得到 %v 与 %v", got, err)
+	}
+	if n := processed.Load(); n > 3 {
+		t.Errorf("FailFast 期望失败后尽快停止，实际处理 %d 个", n)
+	}
+
+	got, err = SelectAsyncOpts(context.Background(), q, oddOnly, ParallelOptions{Workers: 4, FailMode: CollectErrors})
+	if !slices.Equal(got, []int{10, 30, 50, 70, 90}) {
+		t.Errorf("CollectErrors 期望成功结果按输入顺序，实际得到 %v", got)
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 5 {
+		t.Fatalf("CollectErrors 期望合并 5 个错误，实际得到 %v", err)
+	}
+	for i, e := range joined.Unwrap() {
+		if e.(*ItemError).Item != (i+1)*2 {
+			t.Errorf("错误期望按元素顺序排列，第 %d 个为 %v", i, e)
+		}
+	}
+
+	got, err = SelectAsyncOpts(context.Background(), q, oddOnly, ParallelOptions{Workers: 4, FailMode: SkipErrors})
+	if err != nil || !slices.Equal(got, []int{10, 30, 50, 70, 90}) {
+		t.Errorf("SkipErrors 期望 [10 30 50 70 90] 与 nil，实际得到 %v 与 %v", got, err)
+	}
+}
+
+// TestParallelOptsCancelAndPanic 测试上下文取消与 panic
+func TestParallelOptsCancelAndPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	err := QueryRange(0, 1000).WithContext(ctx).ForEachParallelOpts(nil, func(context.Context, int) error {
+		if calls.Add(1) == 3 {
+			cancel()
+		}
+		return nil
+	}, ParallelOptions{Workers: 2, FailMode: CollectErrors})
+	if !errors.Is(err, context.Canceled) || calls.Load() > 10 {
+		t.Errorf("期望取消后尽快返回 context.Canceled，实际调用 %d 次，错误 %v", calls.Load(), err)
+	}
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("期望重新抛出 panic boom，实际得到 %v", r)
+		}
+	}()
+	From([]int{1, 2, 3}).ForEachParallelOpts(context.Background(), func(_ context.Context, i int) error {
+		if i == 2 {
+			panic("boom")
+		}
+		return nil
+	}, ParallelOptions{Workers: 2})
+}
+
+// TestParallelErrCollectsPanics 汇总全部 panic，包含元素、worker 与调用栈
+func TestParallelErrCollectsPanics(t *testing.T) {
+	var processed atomic.Int32
+	err := QueryRange(0, 10).ForEachParallelErr(context.Background(), func(i int) {
+		processed.Add(1)
+		if i%3 == 0 {
+			panic(fmt.Sprint("boom ", i))
+		}
+	}, 3)
+	if processed.Load() != 10 {
+		t.Errorf("期望 panic 后继续处理全部 10 个元素，实际 %d 个", processed.Load())
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 4 {
+		t.Fatalf("期望合并 4 个错误，实际得到 %v", err)
+	}
+	for i, e := range joined.Unwrap() {
+		itemErr := e.(*ItemError)
+		var panicErr *PanicError
+		if itemErr.Item != i*3 || !errors.As(e, &panicErr) || panicErr.Value != fmt.Sprint("boom ", i*3) {
+			t.Errorf("第 %d 个错误期望元素 %d 的 panic，实际得到 %v", i, i*3, e)
+			continue
+		}
+		if itemErr.Worker < 0 || itemErr.Worker > 2 || itemErr.Attempts != 1 {
+			t.Errorf("期望 worker 在 [0, 2] 内且尝试 1 次，实际得到 %+v", itemErr)
+		}
+		if !strings.Contains(string(panicErr.Stack), "TestParallelErrCollectsPanics") {
+			t.Errorf("期望调用栈包含回调所在函数，实际得到 %s", panicErr.Stack)
+		}
+	}
+
+	// panic 值为 error 时可通过 errors.Is 匹配；结果不含 panic 的元素
+	got, err := SelectAsyncErr(context.Background(), QueryRange(1, 5), func(i int) int {
+		if i == 3 {
+			panic(errTransient)
+		}
+		return i * 10
+	}, 2)
+	if !errors.Is(err, errTransient) || !slices.Equal(got, []int{10, 20, 40, 50}) {
+		t.Errorf("期望 [10 20 40 50] 与 errTransient，实际得到 %v 与 %v", got, err)
+	}
+	if got, err := SelectAsyncErr(context.Background(), QueryRange(1, 3), func(i int) int { return i }); err != nil || !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("无 panic 时期望 [1 2 3] 与 nil，实际得到 %v 与 %v", got, err)
+	}
+}
+
+// TestParallelRecoverPanics panic 不重试，并按 FailMode 处理
+func TestParallelRecoverPanics(t *testing.T) {
+	var calls atomic.Int32
+	err := From([]int{4}).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+		calls.Add(1)
+		panic("boom")
+	}, ParallelOptions{RecoverPanics: true, Retry: RetryPolicy{MaxAttempts: 3}, Clock: newFakeClock()})
+	var itemErr *ItemError
+	var panicErr *PanicError
+	if !errors.As(err, &itemErr) || !errors.As(err, &panicErr) || itemErr.Item != 4 || panicErr.Value != "boom" {
+		t.Fatalf("期望元素 4 的 PanicError，实际得到 %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("panic 期望不重试，实际调用 %d 次", calls.Load())
+	}
+
+	err = QueryRange(0, 5).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+		panic("boom")
+	}, ParallelOptions{Workers: 2, RecoverPanics: true, FailMode: SkipErrors})
+	if err != nil {
+		t.Errorf("SkipErrors 期望忽略 panic，实际得到 %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package linq
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// 并行稳定排序：通过 OrderedQuery.Parallel 显式开启，数据按 worker 数分段并行排序，再逐轮两两归并。
+// 结果与 slices.SortStableFunc 完全一致，比较器会被多个 goroutine 同时调用，须可并发执行。
+
+const parallelSortMinRun = 1 << 11 // 每段的最少元素个数
+
+// Parallel 使用并行稳定排序；结果与顺序排序一致，比较器须可并发调用。未调用时总是顺序排序
+func (oq OrderedQuery[T]) Parallel() OrderedQuery[T] {
+	return newOrderedQuery(oq.source(), oq.sortCompares, oq.sortStable, true)
+}
+
+// sortData 按比较器排序 data；仅在指定 parallel 且数据足够分段时并行排序
+func sortData[T any](data []T, cmpFn func(a, b T) int, stable, parallel bool) {
+	if len(data) <= 1 {
+		return
+	}
+	if parallel {
+		if workers := min(runtime.GOMAXPROCS(0), len(data)/parallelSortMinRun); workers > 1 {
+			parallelSortStable(data, cmpFn, workers)
+			return
+		}
+	}
+	if stable {
+		slices.SortStableFunc(data, cmpFn)
+	} else {
+		slices.SortFunc(data, cmpFn)
+	}
+}
+
+// parallelSortStable 分 workers 段并行稳定排序后两两归并，比较器的 panic 在调用方重新抛出
+func parallelSortStable[T any](data []T, cmpFn func(a, b T) int, workers int) {
+	n := len(data)
+	size := (n + workers - 1) / workers
+	bounds := make([]int, 0, workers+1)
+	for lo := 0; lo < n; lo += size {
+		bounds = append(bounds, lo)
+	}
+	bounds = append(bounds, n)
+
+	runAll(len(bounds)-1, func(i int) {
+		slices.SortStableFunc(data[bounds[i]:bounds[i+1]], cmpFn)
+	})
+
+	src, dst := data, make([]T, n)
+	for len(bounds) > 2 {
+		runs := len(bounds) - 1
+		runAll((runs+1)/2, func(i int) {
+			lo, mid := bounds[2*i], bounds[2*i+1]
+			if 2*i+1 == runs {
+				// 奇数段直接复制到下一轮
+				copy(dst[lo:mid], src[lo:mid])
+				return
+			}
+			hi := bounds[2*i+2]
+			mergeStable(dst[lo:hi], src[lo:mid], src[mid:hi], cmpFn)
+		})
+		next := bounds[:0:0]
+		for i := 0; i < len(bounds); i += 2 {
+			next = append(next, bounds[i])
+		}
+		if next[len(next)-1] != n {
+			next = append(next, n)
+		}
+		bounds = next
+		src, dst = dst, src
+	}
+	if &src[0] != &data[0] {
+		copy(data, src)
+	}
+}
+
+// mergeStable 将有序的 a、b 归并到 dst，相等时 a 中的元素在前
+func mergeStable[T any](dst, a, b []T, cmpFn func(a, b T) int) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if cmpFn(b[j], a[i]) < 0 {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// runAll 并发执行 n 个任务并等待完成，任务中的 panic 在调用方重新抛出
+func runAll(n int, task func(i int)) {
+	var wg sync.WaitGroup
+	panicCh := make(chan any, 1)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					select {
+					case panicCh <- r:
+					default:
+					}
+				}
+			}()
+			task(i)
+		}()
+	}
+	wg.Wait()
+	select {
+	case r := <-panicCh:
+		panic(r)
+	default:
+	}
+}
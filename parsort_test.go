@@ -0,0 +1,92 @@
+package linq
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+// TestParallelSortStable 各种长度与分段数下结果与 slices.SortStableFunc 一致
+func TestParallelSortStable(t *testing.T) {
+	// 按十位比较，个位记录原始顺序，用于检查稳定性
+	byTens := func(a, b int) int { return a/10 - b/10 }
+	for _, n := range []int{2, 3, 17, 1000, 4099} {
+		for _, workers := range []int{2, 3, 4, 7} {
+			data := make([]int, n)
+			for i := range data {
+				data[i] = rand.IntN(n/2+1)*10 + i%10
+			}
+			want := slices.Clone(data)
+			slices.SortStableFunc(want, byTens)
+			parallelSortStable(data, byTens, min(workers, n))
+			if !slices.Equal(data, want) {
+				t.Fatalf("长度 %d、%d 段时结果与顺序稳定排序不一致", n, workers)
+			}
+		}
+	}
+}
+
+// TestOrderedQueryParallel Parallel 的排序结果与顺序排序一致，并随 Then、Materialize 保留
+func TestOrderedQueryParallel(t *testing.T) {
+	data := make([]int, 1<<16+100)
+	for i := range data {
+		data[i] = rand.IntN(1000)*1000 + rand.IntN(1000)
+	}
+	want := slices.Clone(data)
+	slices.SortStableFunc(want, func(a, b int) int {
+		if r := a/1000 - b/1000; r != 0 {
+			return r
+		}
+		return b%1000 - a%1000
+	})
+
+	high := Asc(func(i int) int { return i / 1000 })
+	low := Desc(func(i int) int { return i % 1000 })
+	parallel := From(data).Order(high).Parallel().Then(low)
+	if !parallel.sortParallel || !slices.Equal(parallel.ToSlice(), want) {
+		t.Error("Parallel().Then 结果与顺序排序不一致或未保留并行选项")
+	}
+	small := From(data[:5000]).Order(high).Then(low).Parallel().Materialize()
+	wantSmall := slices.Clone(data[:5000])
+	slices.SortStableFunc(wantSmall, composeComparators(small.sortCompares))
+	if !small.sortParallel || !slices.Equal(small.ToSlice(), wantSmall) {
+		t.Error("Parallel 后 Materialize 结果与顺序排序不一致")
+	}
+}
+
+// TestOrderedQuerySequentialByDefault 未调用 Parallel 时无论元素多少都不会并发调用比较器
+func TestOrderedQuerySequentialByDefault(t *testing.T) {
+	// 单核环境下也按多核的分段条件检查
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	data := make([]int, 1<<17)
+	for i := range data {
+		data[i] = rand.IntN(1 << 20)
+	}
+	var inFlight, overlaps atomic.Int32
+	byValue := func(a, b int) int {
+		if inFlight.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		defer inFlight.Add(-1)
+		return a - b
+	}
+	got := From(data).Order(byValue).Then(byValue).ToSlice()
+	if n := overlaps.Load(); n != 0 || !slices.IsSorted(got) {
+		t.Errorf("期望顺序排序且比较器未被并发调用，实际并发 %d 次", n)
+	}
+	if got := OrderBy(From(data), func(i int) int { return -i }).ToSlice(); got[0] != slices.Max(data) {
+		t.Errorf("期望降序首个为最大值，实际得到 %d", got[0])
+	}
+}
+
+// TestParallelSortPanic 比较器的 panic 在调用方重新抛出
+func TestParallelSortPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "bad compare" {
+			t.Errorf("期望重新抛出 panic bad compare，实际得到 %v", r)
+		}
+	}()
+	parallelSortStable(makeRange(0, 100), func(a, b int) int { panic("bad compare") }, 4)
+}
@@ -0,0 +1,138 @@
+package linq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrPoolClosed Pool 已关闭，不再接受任务
+var ErrPoolClosed = errors.New("linq: pool closed")
+
+type poolKey struct{}
+
+// WithPool 返回绑定 p 的上下文：在该上下文下执行的 SelectAsyncCtx、ForEachParallelCtx 及未指定 ParallelOptions.Pool 的
+// *Opts 操作由 p 执行，workers 为本次调用占用的并发上限（不指定时仅受 p 限制）；p 为 nil 时解除绑定
+func WithPool(ctx context.Context, p *Pool) context.Context {
+	return context.WithValue(ctx, poolKey{}, p)
+}
+
+// poolFrom 返回上下文绑定的 Pool，未绑定时返回 nil
+func poolFrom(ctx context.Context) *Pool {
+	p, _ := ctx.Value(poolKey{}).(*Pool)
+	return p
+}
+
+// Pool 可复用的 worker 池：多个查询的并发操作通过 ParallelOptions.Pool 或 WithPool 绑定的上下文共享同一并发上限。
+// 池中执行的回调不能再向同一个池提交任务并等待其完成（如在回调中执行使用该池的并发操作）：
+// 池的 worker 全部被这样的回调占用时，内层任务永远等不到空闲 worker，调用将死锁
+type Pool struct {
+	size      int
+	tasks     chan func(worker int)
+	quit      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+
+	active    atomic.Int64
+	queued    atomic.Int64
+	completed atomic.Int64
+}
+
+// PoolStats Pool 的运行指标
+type PoolStats struct {
+	Size      int   // worker 数
+	Active    int   // 正在执行的任务数
+	Queued    int   // 等待空闲 worker 的任务数
+	Completed int64 // 已完成的任务数
+}
+
+// NewPool 创建包含 size 个 worker 的 Pool，size 小于 1 时按 1 处理；不再使用时应调用 Shutdown
+func NewPool(size int) *Pool {
+	size = max(size, 1)
+	p := &Pool{
+		size:  size,
+		tasks: make(chan func(worker int)),
+		quit:  make(chan struct{}),
+	}
+	p.wg.Add(size)
+	for i := 0; i < size; i++ {
+		go p.work(i)
+	}
+	return p
+}
+
+func (p *Pool) work(worker int) {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.quit:
+			return
+		case task := <-p.tasks:
+			p.active.Add(1)
+			func() {
+				defer func() {
+					p.active.Add(-1)
+					p.completed.Add(1)
+				}()
+				task(worker)
+			}()
+		}
+	}
+}
+
+// submit 等待空闲 worker 执行 task；ctx 取消时返回 ctx.Err()，Pool 关闭后返回 ErrPoolClosed。
+// task 须自行处理 panic
+func (p *Pool) submit(ctx context.Context, task func(worker int)) error {
+	select {
+	case <-p.quit:
+		return ErrPoolClosed
+	default:
+	}
+	p.queued.Add(1)
+	defer p.queued.Add(-1)
+	select {
+	case <-p.quit:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	case p.tasks <- task:
+		return nil
+	}
+}
+
+// Stats 返回当前指标快照
+func (p *Pool) Stats() PoolStats {
+	return PoolStats{
+		Size:      p.size,
+		Active:    int(p.active.Load()),
+		Queued:    int(p.queued.Load()),
+		Completed: p.completed.Load(),
+	}
+}
+
+// Shutdown 停止接受新任务并等待正在执行的任务完成；ctx 先结束时返回 ctx.Err()，任务仍在后台继续执行。
+// 可多次调用
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.closeOnce.Do(func() { close(p.quit) })
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// poolOptions SelectAsyncCtx 与 ForEachParallelCtx 使用 Pool 时的选项，workers 为本次调用占用的并发上限
+func poolOptions(p *Pool, workers []int) ParallelOptions {
+	opts := ParallelOptions{Pool: p}
+	if len(workers) > 0 {
+		opts.Workers = max(workers[0], 0)
+	}
+	return opts
+}
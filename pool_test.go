@@ -0,0 +1,235 @@
+package linq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPoolSharedLimit 两个查询同时使用同一 Pool 时总并发不超过 Pool 大小
+func TestPoolSharedLimit(t *testing.T) {
+	pool := NewPool(3)
+	defer pool.Shutdown(context.Background())
+
+	var running, peak atomic.Int32
+	action := func(context.Context, int) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = QueryRange(0, 30).ForEachParallelOpts(context.Background(), action, ParallelOptions{Pool: pool})
+		}()
+	}
+	wg.Wait()
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatalf("期望无错误，实际得到 %v", errs)
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("期望并发不超过 3，实际峰值 %d", p)
+	}
+	if s := pool.Stats(); s.Completed != 60 || s.Active != 0 || s.Queued != 0 || s.Size != 3 {
+		t.Errorf("期望完成 60 个且无执行中、排队的任务，实际得到 %+v", s)
+	}
+
+	// Workers 限制单次调用占用的并发数
+	peak.Store(0)
+	got, err := SelectAsyncOpts(context.Background(), QueryRange(1, 20), func(ctx context.Context, i int) (int, error) {
+		action(ctx, i)
+		return i * 2, nil
+	}, ParallelOptions{Pool: pool, Workers: 1})
+	if err != nil || len(got) != 20 || got[19] != 40 {
+		t.Errorf("期望按输入顺序得到 20 个结果，实际得到 %v 与 %v", got, err)
+	}
+	if p := peak.Load(); p != 1 {
+		t.Errorf("Workers 为 1 时期望并发峰值为 1，实际 %d", p)
+	}
+}
+
+// TestPoolStats 执行中与排队的任务计入指标
+func TestPoolStats(t *testing.T) {
+	pool := NewPool(1)
+	defer pool.Shutdown(context.Background())
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 3)
+	done := make(chan error)
+	go func() {
+		done <- QueryRange(0, 3).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}, ParallelOptions{Pool: pool})
+	}()
+	<-started
+	deadline := time.Now().Add(2 * time.Second)
+	for pool.Stats().Queued != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if s := pool.Stats(); s.Active != 1 || s.Queued != 1 {
+		t.Errorf("期望 1 个执行中、1 个排队，实际得到 %+v", s)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("期望无错误，实际得到 %v", err)
+	}
+	if s := pool.Stats(); s.Completed != 3 {
+		t.Errorf("期望完成 3 个，实际得到 %+v", s)
+	}
+}
+
+// TestPoolShutdown Shutdown 等待执行中的任务，之后提交返回 ErrPoolClosed
+func TestPoolShutdown(t *testing.T) {
+	pool := NewPool(2)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var finished atomic.Bool
+	go QueryRange(0, 1).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+		close(started)
+		<-release
+		finished.Store(true)
+		return nil
+	}, ParallelOptions{Pool: pool})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("任务未完成时期望 DeadlineExceeded，实际得到 %v", err)
+	}
+	close(release)
+	if err := pool.Shutdown(context.Background()); err != nil || !finished.Load() {
+		t.Errorf("期望等待任务完成后返回 nil，实际得到 %v，完成 %v", err, finished.Load())
+	}
+
+	err := From([]int{1, 2}).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+		t.Error("关闭后不应执行任务")
+		return nil
+	}, ParallelOptions{Pool: pool})
+	if !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("期望 ErrPoolClosed，实际得到 %v", err)
+	}
+}
+
+// TestPoolPanic 任务 panic 在调用方重新抛出，Pool 的 worker 继续可用
+func TestPoolPanic(t *testing.T) {
+	pool := NewPool(1)
+	defer pool.Shutdown(context.Background())
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("期望重新抛出 panic boom，实际得到 %v", r)
+			}
+		}()
+		From([]int{1}).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+			panic("boom")
+		}, ParallelOptions{Pool: pool})
+	}()
+	calls := 0
+	if err := From([]int{1, 2}).ForEachParallelOpts(context.Background(), func(context.Context, int) error {
+		calls++
+		return nil
+	}, ParallelOptions{Pool: pool}); err != nil || calls != 2 {
+		t.Errorf("期望 panic 后 Pool 仍可用，实际调用 %d 次，错误 %v", calls, err)
+	}
+}
+
+// TestWithPool 经 WithPool 绑定后 SelectAsyncCtx 与 ForEachParallelCtx 共享同一 Pool
+func TestWithPool(t *testing.T) {
+	pool := NewPool(2)
+	defer pool.Shutdown(context.Background())
+	ctx := WithPool(context.Background(), pool)
+
+	var running, peak atomic.Int32
+	work := func(i int) int {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return i * 2
+	}
+	var wg sync.WaitGroup
+	var got []int
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		got = SelectAsyncCtx(ctx, QueryRange(1, 20), work, 8).ToSlice()
+	}()
+	go func() {
+		defer wg.Done()
+		QueryRange(1, 20).ForEachParallelCtx(ctx, func(i int) { work(i) }, 8)
+	}()
+	wg.Wait()
+	if sum := Sum(From(got)); len(got) != 20 || sum != 420 {
+		t.Errorf("期望 20 个结果且和为 420，实际得到 %v", got)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("期望并发不超过 Pool 大小 2，实际峰值 %d", p)
+	}
+	if s := pool.Stats(); s.Completed != 40 {
+		t.Errorf("期望 Pool 完成 40 个任务，实际得到 %+v", s)
+	}
+
+	// 绑定在查询上的上下文同样生效；提前结束不泄漏
+	defer checkGoroutines(t)()
+	peak.Store(0)
+	first := SelectAsync(QueryRange(1, 100).WithContext(ctx), work, 4).Take(3).ToSlice()
+	if p := peak.Load(); len(first) != 3 || p > 2 {
+		t.Errorf("期望由 Pool 执行并得到 3 个结果，实际得到 %v，并发峰值 %d", first, p)
+	}
+
+	// panic 在调用方重新抛出
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("期望重新抛出 panic boom，实际得到 %v", r)
+			}
+		}()
+		SelectAsyncCtx(ctx, QueryRange(1, 5), func(i int) int {
+			if i == 3 {
+				panic("boom")
+			}
+			return i
+		}).Count()
+	}()
+}
+
+// TestWithPoolClosed Pool 关闭后以 ErrPoolClosed panic
+func TestWithPoolClosed(t *testing.T) {
+	pool := NewPool(1)
+	pool.Shutdown(context.Background())
+	ctx := WithPool(context.Background(), pool)
+	for name, run := range map[string]func(){
+		"ForEachParallelCtx": func() { QueryRange(1, 3).ForEachParallelCtx(ctx, func(int) {}) },
+		"SelectAsyncCtx":     func() { SelectAsyncCtx(ctx, QueryRange(1, 3), func(i int) int { return i }).Count() },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != ErrPoolClosed {
+					t.Errorf("%s：期望以 ErrPoolClosed panic，实际得到 %v", name, r)
+				}
+			}()
+			run()
+		}()
+	}
+}
@@ -3,6 +3,7 @@ package linq
 import (
 	"cmp"
 	"context"
+	"errors"
 	"maps"
 	"slices"
 	"sync"
@@ -21,6 +22,7 @@ func From[T comparable](source []T) Query[T] {
 // FromChannel 从只读 Channel 创建 Query 查询对象
 func FromChannel[T comparable](source <-chan T) Query[T] {
 	return Query[T]{
+		channel: source,
 		iterate: func(yield func(T) bool) {
 			for item := range source {
 				if !yield(item) {
@@ -31,6 +33,25 @@ func FromChannel[T comparable](source <-chan T) Query[T] {
 	}
 }
 
+// FromChannelCancel 从只读 Channel 创建 Query 查询对象，遍历在通道关闭前结束（如 Take、First）时调用 cancel 通知上游生产者；
+// cancel 至多调用一次；与 WithContext 连用时上下文取消也会调用 cancel
+func FromChannelCancel[T comparable](source <-chan T, cancel func()) Query[T] {
+	var once sync.Once
+	stop := func() { once.Do(cancel) }
+	return Query[T]{
+		channel:     source,
+		channelStop: stop,
+		iterate: func(yield func(T) bool) {
+			for item := range source {
+				if !yield(item) {
+					stop()
+					return
+				}
+			}
+		},
+	}
+}
+
 // FromString 从字符串创建 Query 查询对象，每个元素为一个 UTF-8 字符
 func FromString(source string) Query[string] {
 	return Query[string]{
@@ -330,6 +351,9 @@ func AvgBy[T comparable, R Integer | Float](q Query[T], selector func(T) R) floa
 
 // Contains 判断序列中是否包含指定的元素
 func Contains[T comparable](q Query[T], value T) bool {
+	if q.set != nil {
+		return q.set.Has(value)
+	}
 	return q.AnyWith(func(t T) bool { return t == value })
 }
 
@@ -384,8 +408,12 @@ func LastIndexOf[T comparable](q Query[T], value T) int {
 
 // Distinct 过滤掉重复的元素
 func Distinct[T comparable](q Query[T]) Query[T] {
+	if q.set != nil {
+		return q
+	}
 	capHint := q.capacity/2 + 1
 	result := Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			seen := make(map[T]struct{}, capHint)
 			if q.fastSlice != nil {
@@ -446,6 +474,7 @@ func Distinct[T comparable](q Query[T]) Query[T] {
 func DistinctBy[T, K comparable](q Query[T], selector func(T) K) Query[T] {
 	capHint := q.capacity/2 + 1
 	result := Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			seen := make(map[K]struct{}, capHint)
 			if q.fastSlice != nil {
@@ -508,11 +537,15 @@ func DistinctBy[T, K comparable](q Query[T], selector func(T) K) Query[T] {
 
 // Intersect 获取两个序列的交集
 func Intersect[T comparable](q1, q2 Query[T]) Query[T] {
+	if q2.set != nil {
+		return filterSet(q1, q2.set, true)
+	}
 	capHint := q1.capacity
 	if capHint <= 0 || (q2.capacity > 0 && q2.capacity < capHint) {
 		capHint = q2.capacity
 	}
 	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
 		iterate: func(yield func(T) bool) {
 			// 0: 不存在 1: 存在于 q2 2: 已输出
 			seen := make(map[T]uint8, q2.capacity)
@@ -633,6 +666,7 @@ func IntersectBy[T, K comparable](q1, q2 Query[T], selector func(T) K) Query[T]
 		capHint = q2.capacity
 	}
 	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
 		iterate: func(yield func(T) bool) {
 			// 0: 不存在 1: 存在于 q2 2: 已输出
 			seen := make(map[K]uint8, q2.capacity)
@@ -716,7 +750,11 @@ func IntersectBy[T, K comparable](q1, q2 Query[T], selector func(T) K) Query[T]
 
 // Union 获取两个序列的并集
 func Union[T comparable](q1, q2 Query[T]) Query[T] {
+	if q2.set != nil {
+		return unionSet(q1, q2.set)
+	}
 	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
 		iterate: func(yield func(T) bool) {
 			seen := make(map[T]struct{}, q1.capacity+q2.capacity)
 			if q1.fastSlice != nil {
@@ -812,6 +850,7 @@ func Union[T comparable](q1, q2 Query[T]) Query[T] {
 // UnionBy 根据键选择器获取两个序列的并集
 func UnionBy[T, K comparable](q1, q2 Query[T], selector func(T) K) Query[T] {
 	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
 		iterate: func(yield func(T) bool) {
 			seen := make(map[K]struct{}, q1.capacity+q2.capacity)
 			if q1.fastSlice != nil {
@@ -914,8 +953,12 @@ func UnionBy[T, K comparable](q1, q2 Query[T], selector func(T) K) Query[T] {
 
 // Except 获取两个序列的差集 (q1 中有而 q2 中没有)
 func Except[T comparable](q1, q2 Query[T]) Query[T] {
+	if q2.set != nil {
+		return filterSet(q1, q2.set, false)
+	}
 	capHint := q2.capacity + q1.capacity/2 + 1
 	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
 		iterate: func(yield func(T) bool) {
 			// 0: 不存在 1: 存在于 q2 2: 已从 q1 输出
 			seen := make(map[T]uint8, capHint)
@@ -1033,6 +1076,7 @@ func Except[T comparable](q1, q2 Query[T]) Query[T] {
 func ExceptBy[T, K comparable](q1, q2 Query[T], selector func(T) K) Query[T] {
 	capHint := q2.capacity + q1.capacity/2 + 1
 	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
 		iterate: func(yield func(T) bool) {
 			// 0: 不存在 1: 存在于 q2 2: 已从 q1 输出
 			seen := make(map[K]uint8, capHint)
@@ -1117,6 +1161,7 @@ func ExceptBy[T, K comparable](q1, q2 Query[T], selector func(T) K) Query[T] {
 // Select 将序列中的每个元素投影到新表单
 func Select[T, V comparable](q Query[T], selector func(T) V) Query[V] {
 	result := Query[V]{
+		ctx: q.ctx,
 		iterate: func(yield func(V) bool) {
 			if q.fastSlice != nil {
 				for _, item := range q.fastSlice {
@@ -1166,87 +1211,31 @@ func Select[T, V comparable](q Query[T], selector func(T) V) Query[V] {
 	return result
 }
 
-// SelectAsyncCtx 并发转换元素并返回一个无序序列，若包含 panic 则终止。
+// SelectAsyncCtx 并发转换元素并返回一个无序序列，若包含 panic 则终止；ctx 为 nil 时使用 WithContext 绑定的上下文。
+// 需要汇总全部 panic 时使用 SelectAsyncErr。ctx 经 WithPool 绑定 Pool 时由 Pool 执行，Pool 已关闭时遍历以 ErrPoolClosed panic
 func SelectAsyncCtx[T, V comparable](ctx context.Context, q Query[T], selector func(T) V, workers ...int) Query[V] {
 	if ctx == nil {
-		ctx = context.Background()
+		ctx = firstContext(q.ctx, context.Background())
 	}
 	iworkers := 1
 	if len(workers) > 0 && workers[0] > 0 {
 		iworkers = workers[0]
 	}
+	pool := poolFrom(ctx)
 	return Query[V]{
+		ctx: q.ctx,
 		iterate: func(yield func(V) bool) {
 			workerCtx, cancel := context.WithCancel(ctx)
 			defer cancel()
-			jobs := make(chan T, iworkers)
 			outCh := make(chan V, iworkers)
 			errCh := make(chan any, 1) // 捕获并发 worker 的 panic
 
-			var wg sync.WaitGroup
-			for i := 0; i < iworkers; i++ {
-				wg.Add(1)
-				go func() {
-					defer wg.Done()
-					defer func() {
-						if r := recover(); r != nil {
-							select {
-							case errCh <- r:
-							default:
-							}
-							cancel()
-						}
-					}()
-					for {
-						select {
-						case <-workerCtx.Done():
-							return
-						case item, ok := <-jobs:
-							if !ok {
-								return
-							}
-							val := selector(item)
-							select {
-							case <-workerCtx.Done():
-								return
-							case outCh <- val:
-							}
-						}
-					}
-				}()
+			if pool != nil {
+				go selectOnPool(workerCtx, cancel, q, selector, poolOptions(pool, workers), outCh, errCh)
+			} else {
+				startSelectWorkers(workerCtx, cancel, q, selector, iworkers, outCh, errCh)
 			}
 
-			// 生产者
-			go func() {
-				defer close(jobs)
-				if q.fastSlice != nil {
-					for _, item := range q.fastSlice {
-						if q.fastWhere != nil && !q.fastWhere(item) {
-							continue
-						}
-						select {
-						case <-workerCtx.Done():
-							return
-						case jobs <- item:
-						}
-					}
-					return
-				}
-				for item := range q.iterate {
-					select {
-					case <-workerCtx.Done():
-						return
-					case jobs <- item:
-					}
-				}
-			}()
-
-			// 关闭输出
-			go func() {
-				wg.Wait()
-				close(outCh)
-			}()
-
 			panicIfAny := func() {
 				select {
 				case panicErr := <-errCh:
@@ -1275,9 +1264,107 @@ func SelectAsyncCtx[T, V comparable](ctx context.Context, q Query[T], selector f
 	}
 }
 
+// startSelectWorkers 启动 SelectAsyncCtx 的 worker 与生产者，全部结束后关闭 outCh
+func startSelectWorkers[T, V comparable](workerCtx context.Context, cancel context.CancelFunc, q Query[T], selector func(T) V,
+	iworkers int, outCh chan<- V, errCh chan<- any) {
+	jobs := make(chan T, iworkers)
+	var wg sync.WaitGroup
+	for i := 0; i < iworkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					tracePanic(q.observer, i, r)
+					select {
+					case errCh <- r:
+					default:
+					}
+					cancel()
+				}
+			}()
+			for {
+				select {
+				case <-workerCtx.Done():
+					return
+				case item, ok := <-jobs:
+					if !ok {
+						return
+					}
+					val := selector(item)
+					select {
+					case <-workerCtx.Done():
+						return
+					case outCh <- val:
+					}
+				}
+			}
+		}()
+	}
+
+	// 生产者
+	go func() {
+		defer close(jobs)
+		if q.fastSlice != nil {
+			for _, item := range q.fastSlice {
+				if q.fastWhere != nil && !q.fastWhere(item) {
+					continue
+				}
+				select {
+				case <-workerCtx.Done():
+					return
+				case jobs <- item:
+				}
+			}
+			return
+		}
+		for item := range q.iterate {
+			select {
+			case <-workerCtx.Done():
+				return
+			case jobs <- item:
+			}
+		}
+	}()
+
+	// 关闭输出
+	go func() {
+		wg.Wait()
+		close(outCh)
+	}()
+}
+
+// selectOnPool 由 Pool 执行 SelectAsyncCtx 的转换，结束后关闭 outCh
+func selectOnPool[T, V comparable](workerCtx context.Context, cancel context.CancelFunc, q Query[T], selector func(T) V,
+	opts ParallelOptions, outCh chan<- V, errCh chan<- any) {
+	defer close(outCh)
+	defer func() {
+		if r := recover(); r != nil {
+			select {
+			case errCh <- r:
+			default:
+			}
+			cancel()
+		}
+	}()
+	err := runParallel(workerCtx, q, opts, func(ctx context.Context, _ int, item T) error {
+		val := selector(item)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case outCh <- val:
+			return nil
+		}
+	})
+	if errors.Is(err, ErrPoolClosed) {
+		panic(err)
+	}
+}
+
 // GroupBy 根据键选择器将元素分组
 func GroupBy[T, K comparable](q Query[T], keySelector func(T) K) Query[*KV[K, []T]] {
 	return Query[*KV[K, []T]]{
+		ctx: q.ctx,
 		iterate: func(yield func(*KV[K, []T]) bool) {
 			groups := make(map[K][]T, q.capacity)
 			if q.fastSlice != nil {
@@ -1295,7 +1382,7 @@ func GroupBy[T, K comparable](q Query[T], keySelector func(T) K) Query[*KV[K, []
 				}
 			}
 			for k, v := range groups {
-				if !yield(&KV[K, []T]{Key: k, Value: v}) {
+				if canceled(q.ctx) || !yield(&KV[K, []T]{Key: k, Value: v}) {
 					return
 				}
 			}
@@ -1306,6 +1393,7 @@ func GroupBy[T, K comparable](q Query[T], keySelector func(T) K) Query[*KV[K, []
 // GroupBySelect 先分组后对每组内元素做映射
 func GroupBySelect[T, K, V comparable](q Query[T], keySelector func(T) K, elementSelector func(T) V) Query[*KV[K, []V]] {
 	return Query[*KV[K, []V]]{
+		ctx: q.ctx,
 		iterate: func(yield func(*KV[K, []V]) bool) {
 			groups := make(map[K][]V, q.capacity)
 			if q.fastSlice != nil {
@@ -1323,7 +1411,7 @@ func GroupBySelect[T, K, V comparable](q Query[T], keySelector func(T) K, elemen
 				}
 			}
 			for k, v := range groups {
-				if !yield(&KV[K, []V]{Key: k, Value: v}) {
+				if canceled(q.ctx) || !yield(&KV[K, []V]{Key: k, Value: v}) {
 					return
 				}
 			}
@@ -1369,12 +1457,13 @@ func ToMapSelect[T, K, V comparable](q Query[T], keySelector func(T) K, valueSel
 
 // SelectAsync 并发转换元素而无需手动传递 context
 func SelectAsync[T, V comparable](q Query[T], selector func(T) V, workers ...int) Query[V] {
-	return SelectAsyncCtx(context.Background(), q, selector, workers...)
+	return SelectAsyncCtx(firstContext(q.ctx, context.Background()), q, selector, workers...)
 }
 
 // WhereSelect 选择满足条件并执行变换的元素
 func WhereSelect[T, V comparable](q Query[T], selector func(T) (V, bool)) Query[V] {
 	return Query[V]{
+		ctx: q.ctx,
 		iterate: func(yield func(V) bool) {
 			if q.fastSlice != nil {
 				if q.fastWhere == nil {
@@ -1451,6 +1540,7 @@ func WhereSelect[T, V comparable](q Query[T], selector func(T) (V, bool)) Query[
 func DistinctSelect[T, V comparable](q Query[T], selector func(T) V) Query[V] {
 	capHint := q.capacity/2 + 1
 	result := Query[V]{
+		ctx: q.ctx,
 		iterate: func(yield func(V) bool) {
 			seen := make(map[V]struct{}, capHint)
 			if q.fastSlice != nil {
@@ -1514,6 +1604,7 @@ func DistinctSelect[T, V comparable](q Query[T], selector func(T) V) Query[V] {
 // UnionSelect 映射并合并去重
 func UnionSelect[T, V comparable](q, q2 Query[T], selector func(T) V) Query[V] {
 	return Query[V]{
+		ctx: firstContext(q.ctx, q2.ctx),
 		iterate: func(yield func(V) bool) {
 			seen := make(map[V]struct{}, q.capacity+q2.capacity)
 			if q.fastSlice != nil {
@@ -1621,6 +1712,7 @@ func IntersectSelect[T, V comparable](q, q2 Query[T], selector func(T) V) Query[
 		capHint = q2.capacity
 	}
 	return Query[V]{
+		ctx: firstContext(q.ctx, q2.ctx),
 		iterate: func(yield func(V) bool) {
 			// 0: 不存在 1: 存在于 q2 2: 已输出
 			seen := make(map[V]uint8, q2.capacity)
@@ -1707,6 +1799,7 @@ func IntersectSelect[T, V comparable](q, q2 Query[T], selector func(T) V) Query[
 func ExceptSelect[T, V comparable](q, q2 Query[T], selector func(T) V) Query[V] {
 	capHint := q2.capacity + q.capacity/2 + 1
 	return Query[V]{
+		ctx: firstContext(q.ctx, q2.ctx),
 		iterate: func(yield func(V) bool) {
 			// 0: 不存在 1: 存在于 q2 2: 已输出
 			seen := make(map[V]uint8, capHint)
@@ -2,6 +2,7 @@ package linq
 
 import (
 	"context"
+	"errors"
 	"iter"
 	"slices"
 	"sync"
@@ -47,6 +48,12 @@ type Query[T comparable] struct {
 	sortSource   *Query[T]
 	sortCompares []CompareFunc[T]
 	sortStable   bool
+	sortParallel bool // 并行排序（OrderedQuery.Parallel），随 ThenBy 等保留
+	set          *Set[T]
+	ctx          context.Context // WithContext 指定的上下文，由各操作向下游传递
+	channel      <-chan T        // FromChannel 的源通道，供 WithContext 在等待接收时响应取消
+	channelStop  func()          // FromChannelCancel 的取消回调，遍历在通道关闭前结束时调用
+	observer     Observer        // Trace 指定的观察者，紧随其后的并发操作向其报告 worker 的 panic
 }
 
 // Seq 返回供 for-range 从头到尾遍历的迭代器
@@ -100,28 +107,43 @@ func (q Query[T]) ToSlice() []T {
 	return result
 }
 
-// ToChannel 将查询结果收集为通道，支持上下文取消
+// ToChannel 将查询结果收集为通道，支持上下文取消；ctx 为 nil 时使用 WithContext 绑定的上下文
 func (q Query[T]) ToChannel(ctx context.Context) <-chan T {
+	return q.ToChannelN(ctx, 0)
+}
+
+// ToChannelStop 将查询结果收集为通道并返回停止函数：消费者不再读取时调用 stop，生产者 goroutine 退出并关闭通道，
+// 无需取消 ctx。stop 只发出取消信号、不等待，可多次调用：上游为 FromChannel 源时生产者立即退出，
+// 阻塞在经过其他操作的上游时在上游产生下一个元素或结束时退出。ctx 为 nil 时使用 WithContext 绑定的上下文
+func (q Query[T]) ToChannelStop(ctx context.Context) (<-chan T, func()) {
 	if ctx == nil {
-		ctx = context.Background()
+		ctx = firstContext(q.ctx, context.Background())
 	}
+	ctx, cancel := context.WithCancel(ctx)
 	ch := make(chan T)
 	go func() {
 		defer close(ch)
-		if q.fastSlice != nil {
-			for _, item := range q.fastSlice {
-				if q.fastWhere != nil && !q.fastWhere(item) {
-					continue
-				}
-				select {
-				case <-ctx.Done():
-					return
-				case ch <- item:
-				}
+		// 绑定 ctx 后遍历，上游为通道时等待接收也能响应 stop
+		for item := range q.WithContext(ctx).Seq() {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- item:
 			}
-			return
 		}
-		for item := range q.iterate {
+	}()
+	return ch, cancel
+}
+
+// ToChannelN 将查询结果发送到容量为 size 的缓冲通道，支持上下文取消；ctx 为 nil 时使用 WithContext 绑定的上下文
+func (q Query[T]) ToChannelN(ctx context.Context, size int) <-chan T {
+	if ctx == nil {
+		ctx = firstContext(q.ctx, context.Background())
+	}
+	ch := make(chan T, max(size, 0))
+	go func() {
+		defer close(ch)
+		for item := range q.Seq() {
 			select {
 			case <-ctx.Done():
 				return
@@ -135,6 +157,7 @@ func (q Query[T]) ToChannel(ctx context.Context) <-chan T {
 // Reverse 返回反转后的序列的查询对象
 func (q Query[T]) Reverse() Query[T] {
 	return Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			var items []T
 			if q.fastSlice != nil {
@@ -158,7 +181,7 @@ func (q Query[T]) Reverse() Query[T] {
 				}
 			}
 			for i := len(items) - 1; i >= 0; i-- {
-				if !yield(items[i]) {
+				if canceled(q.ctx) || !yield(items[i]) {
 					return
 				}
 			}
@@ -221,6 +244,21 @@ func (q Query[T]) Except(q2 Query[T]) Query[T] {
 	return Except(q, q2)
 }
 
+// ExceptAll 代理
+func (q Query[T]) ExceptAll(q2 Query[T]) Query[T] {
+	return ExceptAll(q, q2)
+}
+
+// IntersectAll 代理
+func (q Query[T]) IntersectAll(q2 Query[T]) Query[T] {
+	return IntersectAll(q, q2)
+}
+
+// UnionAll 代理
+func (q Query[T]) UnionAll(q2 Query[T]) Query[T] {
+	return UnionAll(q, q2)
+}
+
 // AppendTo 追加到目标切片中
 func (q Query[T]) AppendTo(dest []T) []T {
 	if q.capacity > 0 {
@@ -308,10 +346,22 @@ func (q Query[T]) ForEachIndexed(action func(int, T) bool) {
 	}
 }
 
-// ForEachParallelCtx 支持 Context 取消的并发遍历执行器（不保证顺序）
+// ForEachParallelCtx 支持 Context 取消的并发遍历执行器（不保证顺序）；ctx 为 nil 时使用 WithContext 绑定的上下文。
+// worker panic 时在调用方重新抛出首个 panic，需要汇总全部 panic 时使用 ForEachParallelErr。
+// ctx 经 WithPool 绑定 Pool 时由 Pool 执行，Pool 已关闭时以 ErrPoolClosed panic
 func (q Query[T]) ForEachParallelCtx(ctx context.Context, action func(T), workers ...int) {
 	if ctx == nil {
-		ctx = context.Background()
+		ctx = firstContext(q.ctx, context.Background())
+	}
+	if pool := poolFrom(ctx); pool != nil {
+		err := runParallel(ctx, q, poolOptions(pool, workers), func(_ context.Context, _ int, item T) error {
+			action(item)
+			return nil
+		})
+		if errors.Is(err, ErrPoolClosed) {
+			panic(err)
+		}
+		return
 	}
 	iworkers := 1
 	if len(workers) > 0 && workers[0] > 0 {
@@ -329,6 +379,7 @@ func (q Query[T]) ForEachParallelCtx(ctx context.Context, action func(T), worker
 			defer wg.Done()
 			defer func() {
 				if r := recover(); r != nil {
+					tracePanic(q.observer, i, r)
 					select {
 					case errCh <- r:
 					default:
@@ -384,9 +435,9 @@ func (q Query[T]) ForEachParallelCtx(ctx context.Context, action func(T), worker
 	}
 }
 
-// ForEachParallel 并发遍历无 context (底层封装 Ctx 版本)
+// ForEachParallel 并发遍历无 context (底层封装 Ctx 版本，使用 WithContext 绑定的上下文)
 func (q Query[T]) ForEachParallel(action func(T), workers ...int) {
-	q.ForEachParallelCtx(context.Background(), action, workers...)
+	q.ForEachParallelCtx(firstContext(q.ctx, context.Background()), action, workers...)
 }
 
 // Count 返回序列中的元素个数
@@ -936,6 +987,7 @@ func (q Query[T]) Where(predicate func(T) bool) Query[T] {
 			combinedPred = func(t T) bool { return oldPred(t) && predicate(t) }
 		}
 		return Query[T]{
+			ctx:       q.ctx,
 			iterate:   q.iterate,
 			fastSlice: source,
 			fastWhere: combinedPred,
@@ -943,6 +995,7 @@ func (q Query[T]) Where(predicate func(T) bool) Query[T] {
 		}
 	}
 	return Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			for item := range q.iterate {
 				if predicate(item) {
@@ -958,7 +1011,7 @@ func (q Query[T]) Where(predicate func(T) bool) Query[T] {
 
 // Skip 跳过前 N 个元素
 func (q Query[T]) Skip(count int) Query[T] {
-	if q.fastSlice != nil && q.fastWhere == nil {
+	if q.fastSlice != nil && q.fastWhere == nil && q.ctx == nil {
 		if count >= len(q.fastSlice) {
 			return QueryEmpty[T]()
 		}
@@ -968,6 +1021,7 @@ func (q Query[T]) Skip(count int) Query[T] {
 		return From(q.fastSlice[count:])
 	}
 	return Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			n := count
 			if q.fastSlice != nil {
@@ -1000,7 +1054,7 @@ func (q Query[T]) Skip(count int) Query[T] {
 
 // Take 获取前 N 个元素
 func (q Query[T]) Take(count int) Query[T] {
-	if q.fastSlice != nil && q.fastWhere == nil {
+	if q.fastSlice != nil && q.fastWhere == nil && q.ctx == nil {
 		if count <= 0 {
 			return QueryEmpty[T]()
 		}
@@ -1010,29 +1064,28 @@ func (q Query[T]) Take(count int) Query[T] {
 		return From(q.fastSlice[:count])
 	}
 	return Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			n := count
+			if n <= 0 {
+				return
+			}
 			if q.fastSlice != nil {
 				for _, item := range q.fastSlice {
 					if q.fastWhere != nil && !q.fastWhere(item) {
 						continue
 					}
-					if n <= 0 {
-						break
-					}
 					n--
-					if !yield(item) {
+					if !yield(item) || n <= 0 {
 						break
 					}
 				}
 				return
 			}
+			// 取满后立即停止，避免从惰性源（生成器、Channel）多拉取一个元素
 			for item := range q.iterate {
-				if n <= 0 {
-					break
-				}
 				n--
-				if !yield(item) {
+				if !yield(item) || n <= 0 {
 					break
 				}
 			}
@@ -1046,6 +1099,7 @@ func (q Query[T]) TakeWhile(predicate func(T) bool) Query[T] {
 		source := q.fastSlice
 		preFilter := q.fastWhere
 		return Query[T]{
+			ctx: q.ctx,
 			iterate: func(yield func(T) bool) {
 				for _, item := range source {
 					if preFilter != nil && !preFilter(item) {
@@ -1062,6 +1116,7 @@ func (q Query[T]) TakeWhile(predicate func(T) bool) Query[T] {
 		}
 	}
 	return Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			for item := range q.iterate {
 				if !predicate(item) {
@@ -1081,6 +1136,7 @@ func (q Query[T]) SkipWhile(predicate func(T) bool) Query[T] {
 		source := q.fastSlice
 		preFilter := q.fastWhere
 		return Query[T]{
+			ctx: q.ctx,
 			iterate: func(yield func(T) bool) {
 				skipping := true
 				for _, item := range source {
@@ -1101,6 +1157,7 @@ func (q Query[T]) SkipWhile(predicate func(T) bool) Query[T] {
 		}
 	}
 	return Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			skipping := true
 			for item := range q.iterate {
@@ -1126,6 +1183,7 @@ func (q Query[T]) Page(page, pageSize int) Query[T] {
 // Append 在序列末尾追加
 func (q Query[T]) Append(item T) Query[T] {
 	return Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			if q.fastSlice != nil {
 				source := q.fastSlice
@@ -1153,6 +1211,7 @@ func (q Query[T]) Append(item T) Query[T] {
 // Prepend 在序列开头追加
 func (q Query[T]) Prepend(item T) Query[T] {
 	return Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			if !yield(item) {
 				return
@@ -1182,6 +1241,7 @@ func (q Query[T]) Prepend(item T) Query[T] {
 // Concat 连接两个序列
 func (q Query[T]) Concat(q2 Query[T]) Query[T] {
 	return Query[T]{
+		ctx: firstContext(q.ctx, q2.ctx),
 		iterate: func(yield func(T) bool) {
 			if q.fastSlice != nil {
 				source := q.fastSlice
@@ -1262,13 +1322,14 @@ func (q Query[T]) Concat(q2 Query[T]) Query[T] {
 
 // DefaultIfEmpty 如果空则返回默认值
 func (q Query[T]) DefaultIfEmpty(defaultValue T) Query[T] {
-	if q.fastSlice != nil && q.fastWhere == nil {
+	if q.fastSlice != nil && q.fastWhere == nil && q.ctx == nil {
 		if len(q.fastSlice) == 0 {
 			return From([]T{defaultValue})
 		}
 		return q
 	}
 	return Query[T]{
+		ctx: q.ctx,
 		iterate: func(yield func(T) bool) {
 			empty := true
 			if q.fastSlice != nil {
@@ -0,0 +1,207 @@
+package linq
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"strings"
+	"unsafe"
+)
+
+// 基数排序：OrderBy 系列的键为整数或字符串（含以其为底层类型的自定义类型）且没有其他排序规则时，
+// 预先计算每个元素的键，整数按字节 LSD、字符串按字节 MSD 排序，结果与稳定比较排序一致。
+
+const (
+	radixSortMin   = 256 // 元素个数不少于该值时使用基数排序
+	radixBucketMin = 32  // 字符串 MSD 中小于该值的桶改用比较排序
+)
+
+// orderByKey 按单一键排序，可用时附带基数排序
+func orderByKey[T comparable, K cmp.Ordered](q Query[T], key func(T) K, desc, stable bool) Query[T] {
+	cmpFn := func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+	if desc {
+		cmpFn = func(a, b T) int {
+			return cmp.Compare(key(b), key(a)) // 降序关键：b 与 a 比较
+		}
+	}
+	if q.sortSource != nil || q.HasOrder() {
+		return orderByWithMode(q, cmpFn, stable)
+	}
+	return sortedQuery(q, []CompareFunc[T]{cmpFn}, stable, false, radixSorter(key, desc))
+}
+
+// radixSorter 返回按 key 基数排序的函数，K 不是整数或字符串时返回 nil
+func radixSorter[T any, K cmp.Ordered](key func(T) K, desc bool) func([]T) {
+	kind := reflect.TypeFor[K]().Kind()
+	if kind == reflect.String {
+		return func(data []T) {
+			radixSortStrings(data, func(item T) string {
+				k := key(item)
+				return *(*string)(unsafe.Pointer(&k))
+			}, desc)
+		}
+	}
+	bits := integerBits[K](kind)
+	if bits == nil {
+		return nil
+	}
+	return func(data []T) {
+		radixSortUints(data, func(item T) uint64 { return bits(key(item)) }, desc)
+	}
+}
+
+// integerBits 返回将整数键映射为保持大小顺序的 uint64 的函数，kind 不是整数时返回 nil
+func integerBits[K any](kind reflect.Kind) func(K) uint64 {
+	const signBit = 1 << 63
+	switch kind {
+	case reflect.Int:
+		return func(k K) uint64 { return uint64(*(*int)(unsafe.Pointer(&k))) ^ signBit }
+	case reflect.Int8:
+		return func(k K) uint64 { return uint64(*(*int8)(unsafe.Pointer(&k))) ^ signBit }
+	case reflect.Int16:
+		return func(k K) uint64 { return uint64(*(*int16)(unsafe.Pointer(&k))) ^ signBit }
+	case reflect.Int32:
+		return func(k K) uint64 { return uint64(*(*int32)(unsafe.Pointer(&k))) ^ signBit }
+	case reflect.Int64:
+		return func(k K) uint64 { return uint64(*(*int64)(unsafe.Pointer(&k))) ^ signBit }
+	case reflect.Uint:
+		return func(k K) uint64 { return uint64(*(*uint)(unsafe.Pointer(&k))) }
+	case reflect.Uint8:
+		return func(k K) uint64 { return uint64(*(*uint8)(unsafe.Pointer(&k))) }
+	case reflect.Uint16:
+		return func(k K) uint64 { return uint64(*(*uint16)(unsafe.Pointer(&k))) }
+	case reflect.Uint32:
+		return func(k K) uint64 { return uint64(*(*uint32)(unsafe.Pointer(&k))) }
+	case reflect.Uint64:
+		return func(k K) uint64 { return *(*uint64)(unsafe.Pointer(&k)) }
+	case reflect.Uintptr:
+		return func(k K) uint64 { return uint64(*(*uintptr)(unsafe.Pointer(&k))) }
+	}
+	return nil
+}
+
+type radixEntry[K any] struct {
+	key K
+	idx int
+}
+
+// permute 按排序后的下标重排 data
+func permute[T, K any](data []T, entries []radixEntry[K]) {
+	out := make([]T, len(data))
+	for i, e := range entries {
+		out[i] = data[e.idx]
+	}
+	copy(data, out)
+}
+
+// radixSortUints 按 uint64 键 LSD 基数排序，跳过所有键都相同的字节；降序时对键取反。返回分桶的轮数
+func radixSortUints[T any](data []T, key func(T) uint64, desc bool) (passes int) {
+	src := make([]radixEntry[uint64], len(data))
+	for i, item := range data {
+		k := key(item)
+		if desc {
+			k = ^k
+		}
+		src[i] = radixEntry[uint64]{k, i}
+	}
+	// diff 中为 0 的位在所有键中都相同
+	var diff uint64
+	for _, e := range src {
+		diff |= e.key ^ src[0].key
+	}
+	if diff == 0 {
+		return 0
+	}
+	dst := make([]radixEntry[uint64], len(data))
+	for shift := 0; shift < 64; shift += 8 {
+		if (diff>>shift)&0xff == 0 {
+			continue
+		}
+		var offsets [256]int
+		for _, e := range src {
+			offsets[byte(e.key>>shift)]++
+		}
+		sum := 0
+		for b, c := range offsets {
+			offsets[b] = sum
+			sum += c
+		}
+		for _, e := range src {
+			b := byte(e.key >> shift)
+			dst[offsets[b]] = e
+			offsets[b]++
+		}
+		src, dst = dst, src
+		passes++
+	}
+	permute(data, src)
+	return passes
+}
+
+// radixSortStrings 按字符串键 MSD 基数排序
+func radixSortStrings[T any](data []T, key func(T) string, desc bool) {
+	entries := make([]radixEntry[string], len(data))
+	for i, item := range data {
+		entries[i] = radixEntry[string]{key(item), i}
+	}
+	msdSort(entries, make([]radixEntry[string], len(data)), 0, desc)
+	permute(data, entries)
+}
+
+// msdSort 对前 depth 个字节均相同的 entries 按第 depth 个字节分桶（已结束的字符串最小），buf 为等长的暂存区
+func msdSort(entries, buf []radixEntry[string], depth int, desc bool) {
+	for {
+		if len(entries) < radixBucketMin {
+			slices.SortStableFunc(entries, func(a, b radixEntry[string]) int {
+				if desc {
+					a, b = b, a
+				}
+				return strings.Compare(a.key[depth:], b.key[depth:])
+			})
+			return
+		}
+		var counts [257]int
+		for _, e := range entries {
+			counts[msdBucket(e.key, depth)]++
+		}
+		if counts[0] == 0 && slices.Contains(counts[1:], len(entries)) {
+			// 所有字符串在该字节相同，直接比较下一个字节
+			depth++
+			continue
+		}
+		var offsets [257]int
+		sum := 0
+		for i := range counts {
+			b := i
+			if desc {
+				b = 256 - i
+			}
+			offsets[b] = sum
+			sum += counts[b]
+		}
+		for _, e := range entries {
+			b := msdBucket(e.key, depth)
+			buf[offsets[b]] = e
+			offsets[b]++
+		}
+		copy(entries, buf)
+		// offsets[b] 此时为桶 b 的结束位置
+		for b := 1; b < 257; b++ {
+			if c := counts[b]; c > 1 {
+				end := offsets[b]
+				msdSort(entries[end-c:end], buf[end-c:end], depth+1, desc)
+			}
+		}
+		return
+	}
+}
+
+// msdBucket 第 depth 个字节所在的桶，字符串已结束时为 0
+func msdBucket(s string, depth int) int {
+	if depth >= len(s) {
+		return 0
+	}
+	return int(s[depth]) + 1
+}
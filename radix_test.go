@@ -0,0 +1,79 @@
+package linq
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type radixID int32
+
+// checkRadixOrder 比较 OrderBy / OrderByDescending 与稳定比较排序的结果
+func checkRadixOrder[K cmp.Ordered](t *testing.T, name string, data []int, key func(int) K) {
+	t.Helper()
+	asc := slices.Clone(data)
+	slices.SortStableFunc(asc, func(a, b int) int { return cmp.Compare(key(a), key(b)) })
+	desc := slices.Clone(data)
+	slices.SortStableFunc(desc, func(a, b int) int { return cmp.Compare(key(b), key(a)) })
+	if got := OrderBy(From(data), key).ToSlice(); !slices.Equal(got, asc) {
+		t.Errorf("%s 升序结果与稳定排序不一致", name)
+	}
+	if got := OrderByDescending(From(data), key).ToSlice(); !slices.Equal(got, desc) {
+		t.Errorf("%s 降序结果与稳定排序不一致", name)
+	}
+	if got := OrderByUnstable(From(data), key).ToSlice(); !slices.IsSortedFunc(got, func(a, b int) int { return cmp.Compare(key(a), key(b)) }) {
+		t.Errorf("%s 不稳定升序结果未排序", name)
+	}
+}
+
+// TestOrderByRadix 整数与字符串键的基数排序结果与稳定比较排序一致
+func TestOrderByRadix(t *testing.T) {
+	data := make([]int, 3000)
+	for i := range data {
+		data[i] = rand.IntN(1 << 20)
+	}
+	if radixSorter(func(i int) int { return i }, false) == nil || radixSorter(func(i int) float64 { return 0 }, false) != nil {
+		t.Fatal("期望整数键启用、浮点键不启用基数排序")
+	}
+
+	checkRadixOrder(t, "int", data, func(i int) int { return i - 1<<19 })
+	checkRadixOrder(t, "int8", data, func(i int) int8 { return int8(i) })
+	checkRadixOrder(t, "uint16", data, func(i int) uint16 { return uint16(i >> 4) })
+	checkRadixOrder(t, "uint64", data, func(i int) uint64 { return uint64(i) << 40 })
+	checkRadixOrder(t, "自定义类型", data, func(i int) radixID { return radixID(i%100 - 50) })
+	checkRadixOrder(t, "相同键", data, func(int) int64 { return 7 })
+
+	// 所有键相同时不分桶，只有低位字节不同时只排一轮
+	if passes := radixSortUints(slices.Clone(data), func(int) uint64 { return 7 }, false); passes != 0 {
+		t.Errorf("相同键期望 0 轮，实际 %d 轮", passes)
+	}
+	lowByte := slices.Clone(data)
+	if passes := radixSortUints(lowByte, func(i int) uint64 { return 1<<40 | uint64(i%200) }, true); passes != 1 {
+		t.Errorf("仅低位字节不同期望 1 轮，实际 %d 轮", passes)
+	}
+	if !slices.IsSortedFunc(lowByte, func(a, b int) int { return cmp.Compare(b%200, a%200) }) {
+		t.Errorf("仅低位字节不同时降序结果错误")
+	}
+
+	words := []string{"", "a", "ab", "abc", "b", "ba", "中文", "prefix-long-shared-"}
+	checkRadixOrder(t, "string", data, func(i int) string {
+		return words[i%len(words)] + strings.Repeat("x", i%3)
+	})
+	checkRadixOrder(t, "长公共前缀", data, func(i int) string {
+		return "shared/prefix/" + string(rune('a'+i%26)) + string(rune('a'+i%7))
+	})
+
+	// ThenBy 追加规则后按组合比较器排序
+	want := slices.Clone(data)
+	slices.SortStableFunc(want, func(a, b int) int {
+		if r := cmp.Compare(a%10, b%10); r != 0 {
+			return r
+		}
+		return cmp.Compare(b, a)
+	})
+	if got := ThenByDescending(OrderBy(From(data), func(i int) int { return i % 10 }), func(i int) int { return i }).ToSlice(); !slices.Equal(got, want) {
+		t.Error("OrderBy 后 ThenByDescending 结果错误")
+	}
+}
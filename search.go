@@ -0,0 +1,102 @@
+package linq
+
+import (
+	"slices"
+)
+
+// 有序查找：基于 OrderedQuery 的组合比较器在排序结果上二分查找，查找目标为与元素同类型的探针，
+// 仅比较器用到的字段有意义。未 Materialize 时每次调用都会重新排序。
+
+// Materialize 立即排序并缓存结果，之后的查找与遍历不再重复排序；Then 追加规则后会重新排序
+func (oq OrderedQuery[T]) Materialize() OrderedQuery[T] {
+	if oq.sorted != nil {
+		return oq
+	}
+	data := oq.sortedSlice()
+	if data == nil {
+		data = []T{}
+	}
+	q := From(data)
+	q.compare = composeComparators(oq.sortCompares)
+	q.sortCompares = oq.sortCompares
+	q.sortStable = oq.sortStable
+	q.sortParallel = oq.sortParallel
+	q.ctx = oq.ctx
+	return OrderedQuery[T]{
+		Query:        q,
+		sortCompares: oq.sortCompares,
+		sortStable:   oq.sortStable,
+		sortParallel: oq.sortParallel,
+		sorted:       data,
+	}
+}
+
+// BinarySearch 查找 target，返回第一个比较结果不小于 target 的位置以及是否找到比较结果相等的元素
+func (oq OrderedQuery[T]) BinarySearch(target T) (int, bool) {
+	data, cmpFn := oq.lookup()
+	i := lowerBound(data, target, cmpFn)
+	return i, i < len(data) && cmpFn(data[i], target) == 0
+}
+
+// LowerBound 返回第一个不小于 target 的元素位置，不存在时返回结果长度
+func (oq OrderedQuery[T]) LowerBound(target T) int {
+	data, cmpFn := oq.lookup()
+	return lowerBound(data, target, cmpFn)
+}
+
+// UpperBound 返回第一个大于 target 的元素位置，不存在时返回结果长度
+func (oq OrderedQuery[T]) UpperBound(target T) int {
+	data, cmpFn := oq.lookup()
+	return upperBound(data, target, cmpFn)
+}
+
+// Range 返回排序结果中位于 [lo, hi) 的元素，hi 不大于 lo 时为空
+func (oq OrderedQuery[T]) Range(lo, hi T) Query[T] {
+	data, cmpFn := oq.lookup()
+	start := lowerBound(data, lo, cmpFn)
+	end := max(start, lowerBound(data, hi, cmpFn))
+	return From(data[start:end:end])
+}
+
+// EqualRange 返回排序结果中与 target 比较结果相等的全部元素
+func (oq OrderedQuery[T]) EqualRange(target T) Query[T] {
+	data, cmpFn := oq.lookup()
+	start := lowerBound(data, target, cmpFn)
+	end := start + upperBound(data[start:], target, cmpFn)
+	return From(data[start:end:end])
+}
+
+// view 返回排序结果，已缓存时直接返回缓存（调用方不得修改）
+func (oq OrderedQuery[T]) view() []T {
+	if oq.sorted != nil {
+		return oq.sorted
+	}
+	return oq.sortedSlice()
+}
+
+// lookup 返回查找所需的排序结果与比较器，未定义排序规则时所有元素视为相等
+func (oq OrderedQuery[T]) lookup() ([]T, CompareFunc[T]) {
+	cmpFn := composeComparators(oq.sortCompares)
+	if cmpFn == nil {
+		cmpFn = func(T, T) int { return 0 }
+	}
+	return oq.view(), cmpFn
+}
+
+func lowerBound[T any](data []T, target T, cmpFn func(T, T) int) int {
+	i, _ := slices.BinarySearchFunc(data, target, cmpFn)
+	return i
+}
+
+func upperBound[T any](data []T, target T, cmpFn func(T, T) int) int {
+	lo, hi := 0, len(data)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if cmpFn(data[mid], target) <= 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
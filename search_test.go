@@ -0,0 +1,103 @@
+package linq
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestOrderedQuerySearch 测试有序查找
+func TestOrderedQuerySearch(t *testing.T) {
+	asc := Asc(func(i int) int { return i })
+	oq := From([]int{7, 1, 5, 3, 5, 9, 5}).Order(asc)
+
+	if i, ok := oq.BinarySearch(5); i != 2 || !ok {
+		t.Errorf("BinarySearch(5) 期望 (2, true)，实际得到 (%d, %v)", i, ok)
+	}
+	if i, ok := oq.BinarySearch(4); i != 2 || ok {
+		t.Errorf("BinarySearch(4) 期望 (2, false)，实际得到 (%d, %v)", i, ok)
+	}
+	if lo, hi := oq.LowerBound(5), oq.UpperBound(5); lo != 2 || hi != 5 {
+		t.Errorf("LowerBound/UpperBound 期望 2/5，实际得到 %d/%d", lo, hi)
+	}
+	if got := oq.UpperBound(100); got != 7 {
+		t.Errorf("UpperBound 越界期望 7，实际得到 %d", got)
+	}
+	if got := oq.Range(3, 7).ToSlice(); !slices.Equal(got, []int{3, 5, 5, 5}) {
+		t.Errorf("Range(3, 7) 错误: %v", got)
+	}
+	if got := oq.Range(7, 3).Count(); got != 0 {
+		t.Errorf("Range 反向区间期望为空，实际得到 %d 个", got)
+	}
+	if got := oq.EqualRange(5).Count(); got != 3 {
+		t.Errorf("EqualRange(5) 期望 3 个，实际得到 %d 个", got)
+	}
+	if got := oq.IndexOf(9); got != 6 {
+		t.Errorf("IndexOf(9) 期望 6，实际得到 %d", got)
+	}
+	if got := oq.IndexOf(4); got != -1 {
+		t.Errorf("IndexOf(4) 期望 -1，实际得到 %d", got)
+	}
+
+	// 多级排序下以探针元素查找，并列段内按 == 定位
+	type person struct {
+		Name string
+		Age  int
+	}
+	people := From([]person{{"c", 30}, {"a", 20}, {"b", 20}, {"d", 40}}).
+		Order(Asc(func(p person) int { return p.Age })).
+		Then(Asc(func(p person) string { return p.Name }))
+	if got := people.IndexOf(person{"b", 20}); got != 1 {
+		t.Errorf("IndexOf 期望 1，实际得到 %d", got)
+	}
+	if got := people.Range(person{Age: 20}, person{Age: 40}).ToSlice(); len(got) != 3 || got[2].Name != "c" {
+		t.Errorf("多级排序 Range 错误: %v", got)
+	}
+
+	// 降序排序下的查找
+	desc := From([]int{1, 4, 2, 8}).Order(Desc(func(i int) int { return i }))
+	if got := desc.Range(8, 2).ToSlice(); !slices.Equal(got, []int{8, 4}) {
+		t.Errorf("降序 Range 错误: %v", got)
+	}
+}
+
+// TestOrderedQueryMaterialize 测试缓存排序结果
+func TestOrderedQueryMaterialize(t *testing.T) {
+	calls := 0
+	counting := func(a, b int) int {
+		calls++
+		return a - b
+	}
+	m := From([]int{9, 3, 7, 1, 5}).Order(counting).Materialize()
+	sortCalls := calls
+	for _, v := range []int{1, 3, 5, 7, 9} {
+		if _, ok := m.BinarySearch(v); !ok {
+			t.Errorf("BinarySearch(%d) 应找到", v)
+		}
+	}
+	// 每次查找至多 log2(n)+2 次比较，重新排序则远超此数
+	if calls-sortCalls > 5*5 {
+		t.Errorf("Materialize 后查找不应重新排序，比较次数 %d", calls-sortCalls)
+	}
+	if got := m.ToSlice(); !slices.Equal(got, []int{1, 3, 5, 7, 9}) {
+		t.Errorf("ToSlice 错误: %v", got)
+	}
+
+	// 修改返回结果不影响缓存
+	m.ToSlice()[0] = 100
+	m.Range(1, 5).ToSlice()[0] = 100
+	if got := m.First(); got != 1 {
+		t.Errorf("缓存被修改，First 期望 1，实际得到 %d", got)
+	}
+	if got := m.Count(); got != 5 {
+		t.Errorf("Count 期望 5，实际得到 %d", got)
+	}
+
+	// Then 追加规则后重新排序
+	thenDesc := m.Then(Desc(func(i int) int { return i }))
+	if got := thenDesc.ToSlice(); !slices.Equal(got, []int{1, 3, 5, 7, 9}) {
+		t.Errorf("Then 后结果错误: %v", got)
+	}
+	if got := QueryEmpty[int]().Order(counting).Materialize().Range(0, 10).Count(); got != 0 {
+		t.Errorf("空序列 Range 期望为空，实际得到 %d 个", got)
+	}
+}
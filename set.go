@@ -0,0 +1,286 @@
+package linq
+
+import (
+	"encoding/json"
+	"iter"
+)
+
+// Set 按插入顺序遍历的泛型集合，零值可直接使用
+type Set[T comparable] struct {
+	index   map[T]int
+	entries []setEntry[T]
+	removed int
+}
+
+type setEntry[T comparable] struct {
+	value   T
+	deleted bool
+}
+
+// NewSet 创建集合并添加初始元素
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := newSet[T](len(items))
+	s.Add(items...)
+	return s
+}
+
+// newSet 创建预留 capacity 个元素空间的空集合
+func newSet[T comparable](capacity int) *Set[T] {
+	return &Set[T]{
+		index:   make(map[T]int, capacity),
+		entries: make([]setEntry[T], 0, capacity),
+	}
+}
+
+// ToSet 将查询结果收集为集合
+func ToSet[T comparable](q Query[T]) *Set[T] {
+	if q.set != nil {
+		return q.set.Clone()
+	}
+	s := newSet[T](q.capacity)
+	for item := range q.Seq() {
+		s.Add(item)
+	}
+	return s
+}
+
+// FromSet 从集合创建 Query 查询对象，集合运算的第二个参数传入此查询时直接复用集合而无需重新建表
+func FromSet[T comparable](s *Set[T]) Query[T] {
+	return Query[T]{
+		iterate:  s.All(),
+		capacity: s.Len(),
+		set:      s,
+	}
+}
+
+// Len 返回元素个数
+func (s *Set[T]) Len() int {
+	return len(s.index)
+}
+
+// Has 判断是否包含元素
+func (s *Set[T]) Has(item T) bool {
+	_, ok := s.index[item]
+	return ok
+}
+
+// Add 添加元素，已存在的元素保持原有位置，返回新增的元素个数
+func (s *Set[T]) Add(items ...T) int {
+	if s.index == nil {
+		s.index = make(map[T]int, len(items))
+	}
+	added := 0
+	for _, item := range items {
+		if _, ok := s.index[item]; ok {
+			continue
+		}
+		s.index[item] = len(s.entries)
+		s.entries = append(s.entries, setEntry[T]{value: item})
+		added++
+	}
+	return added
+}
+
+// Remove 移除元素，返回实际移除的元素个数
+func (s *Set[T]) Remove(items ...T) int {
+	removed := 0
+	for _, item := range items {
+		i, ok := s.index[item]
+		if !ok {
+			continue
+		}
+		delete(s.index, item)
+		s.entries[i].deleted = true
+		var zero T
+		s.entries[i].value = zero
+		s.removed++
+		removed++
+	}
+	// 删除标记过半时压缩，保证遍历与内存开销
+	if s.removed > 16 && s.removed*2 >= len(s.entries) {
+		s.compact()
+	}
+	return removed
+}
+
+func (s *Set[T]) compact() {
+	entries := make([]setEntry[T], 0, len(s.index))
+	for _, e := range s.entries {
+		if e.deleted {
+			continue
+		}
+		s.index[e.value] = len(entries)
+		entries = append(entries, e)
+	}
+	s.entries = entries
+	s.removed = 0
+}
+
+// Clear 清空集合
+func (s *Set[T]) Clear() {
+	clear(s.index)
+	s.entries = s.entries[:0]
+	s.removed = 0
+}
+
+// All 按插入顺序返回元素迭代器
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, e := range s.entries {
+			if e.deleted {
+				continue
+			}
+			if !yield(e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Values 按插入顺序返回元素切片
+func (s *Set[T]) Values() []T {
+	result := make([]T, 0, s.Len())
+	for _, e := range s.entries {
+		if !e.deleted {
+			result = append(result, e.value)
+		}
+	}
+	return result
+}
+
+// Clone 复制集合
+func (s *Set[T]) Clone() *Set[T] {
+	return NewSet(s.Values()...)
+}
+
+// Union 返回并集，先保留 s 的顺序，再追加 other 中独有的元素
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	result := s.Clone()
+	for item := range other.All() {
+		result.Add(item)
+	}
+	return result
+}
+
+// Intersect 返回交集，按 s 的顺序
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for item := range s.All() {
+		if other.Has(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// Difference 返回差集（s 中有而 other 中没有），按 s 的顺序
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for item := range s.All() {
+		if !other.Has(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// SymmetricDifference 返回对称差集（仅存在于其中一个集合的元素）
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	result := s.Difference(other)
+	for item := range other.All() {
+		if !s.Has(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// IsSubset 判断 s 是否为 other 的子集
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for item := range s.All() {
+		if !other.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset 判断 s 是否为 other 的超集
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal 判断两个集合元素是否相同（忽略顺序）
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// MarshalJSON 按插入顺序序列化为 JSON 数组
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Values())
+}
+
+// UnmarshalJSON 从 JSON 数组反序列化，重复元素自动去重
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	s.Clear()
+	s.Add(items...)
+	return nil
+}
+
+// filterSet 按集合成员关系过滤 q 并去重，keep 为 true 取交集，为 false 取差集
+func filterSet[T comparable](q Query[T], s *Set[T], keep bool) Query[T] {
+	return Query[T]{
+		ctx: q.ctx,
+		iterate: func(yield func(T) bool) {
+			seen := make(map[T]struct{})
+			for item := range q.Seq() {
+				if s.Has(item) != keep {
+					continue
+				}
+				if _, ok := seen[item]; ok {
+					continue
+				}
+				seen[item] = struct{}{}
+				if !yield(item) {
+					return
+				}
+			}
+		},
+		capacity: q.capacity,
+	}
+}
+
+// unionSet 先输出 q 去重后的元素，再输出集合中未出现过的元素，集合元素无需重新建表
+func unionSet[T comparable](q Query[T], s *Set[T]) Query[T] {
+	return Query[T]{
+		ctx: q.ctx,
+		iterate: func(yield func(T) bool) {
+			seen := make(map[T]struct{}, q.capacity)
+			for item := range q.Seq() {
+				if _, ok := seen[item]; ok {
+					continue
+				}
+				seen[item] = struct{}{}
+				if !yield(item) {
+					return
+				}
+			}
+			for item := range s.All() {
+				if _, ok := seen[item]; ok {
+					continue
+				}
+				if !yield(item) {
+					return
+				}
+			}
+		},
+		capacity: q.capacity + s.Len(),
+	}
+}
@@ -0,0 +1,147 @@
+package linq
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// TestSetBasic 测试集合基本操作
+func TestSetBasic(t *testing.T) {
+	s := NewSet(3, 1, 2, 1)
+	if s.Len() != 3 || !slices.Equal(s.Values(), []int{3, 1, 2}) {
+		t.Errorf("期望按插入顺序 [3 1 2]，实际得到 %v", s.Values())
+	}
+	if n := s.Add(2, 4); n != 1 {
+		t.Errorf("Add 期望新增 1 个，实际 %d 个", n)
+	}
+	if !s.Has(4) || s.Has(5) {
+		t.Errorf("Has 结果错误")
+	}
+	if n := s.Remove(1, 5); n != 1 || s.Has(1) {
+		t.Errorf("Remove 期望移除 1 个，实际 %d 个", n)
+	}
+	s.Add(1)
+	if !slices.Equal(s.Values(), []int{3, 2, 4, 1}) {
+		t.Errorf("重新添加应追加到末尾，实际得到 %v", s.Values())
+	}
+
+	var zero Set[string]
+	zero.Add("a")
+	if !zero.Has("a") || zero.Len() != 1 {
+		t.Errorf("零值集合应可直接使用")
+	}
+	zero.Clear()
+	if zero.Len() != 0 || len(zero.Values()) != 0 {
+		t.Errorf("Clear 后应为空")
+	}
+}
+
+// TestSetCompact 测试大量删除后的压缩与顺序
+func TestSetCompact(t *testing.T) {
+	s := ToSet(QueryRange(0, 100))
+	for i := 0; i < 100; i += 2 {
+		s.Remove(i)
+	}
+	if s.Len() != 50 || len(s.entries) > 60 {
+		t.Errorf("删除过半后应压缩，长度 %d，条目 %d", s.Len(), len(s.entries))
+	}
+	values := s.Values()
+	if values[0] != 1 || values[49] != 99 || !slices.IsSorted(values) {
+		t.Errorf("压缩后应保持顺序: %v", values)
+	}
+	s.Remove(1)
+	if s.Has(1) || !s.Has(3) {
+		t.Errorf("压缩后索引错误")
+	}
+}
+
+// TestSetAlgebra 测试集合运算
+func TestSetAlgebra(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(6, 4, 3, 5)
+
+	if got := a.Union(b).Values(); !slices.Equal(got, []int{1, 2, 3, 4, 6, 5}) {
+		t.Errorf("Union 错误: %v", got)
+	}
+	if got := a.Intersect(b).Values(); !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("Intersect 错误: %v", got)
+	}
+	if got := a.Difference(b).Values(); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Difference 错误: %v", got)
+	}
+	if got := a.SymmetricDifference(b).Values(); !slices.Equal(got, []int{1, 2, 6, 5}) {
+		t.Errorf("SymmetricDifference 错误: %v", got)
+	}
+	if !NewSet(3, 4).IsSubset(a) || a.IsSubset(b) || !a.IsSuperset(NewSet(1)) {
+		t.Errorf("IsSubset/IsSuperset 错误")
+	}
+	if !a.Equal(NewSet(4, 3, 2, 1)) || a.Equal(b) {
+		t.Errorf("Equal 错误")
+	}
+}
+
+// TestSetJSON 测试集合 JSON 序列化
+func TestSetJSON(t *testing.T) {
+	data, err := json.Marshal(NewSet("b", "a", "c"))
+	if err != nil || string(data) != `["b","a","c"]` {
+		t.Errorf("MarshalJSON 错误: %s %v", data, err)
+	}
+	var s Set[string]
+	if err := json.Unmarshal([]byte(`["x","y","x"]`), &s); err != nil {
+		t.Fatalf("UnmarshalJSON 错误: %v", err)
+	}
+	if !slices.Equal(s.Values(), []string{"x", "y"}) {
+		t.Errorf("反序列化应去重: %v", s.Values())
+	}
+	type payload struct {
+		Tags *Set[int] `json:"tags"`
+	}
+	var p payload
+	if err := json.Unmarshal([]byte(`{"tags":[1,2,2]}`), &p); err != nil || p.Tags.Len() != 2 {
+		t.Errorf("嵌套字段反序列化错误: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"a":1}`), &s); err == nil {
+		t.Errorf("非数组应返回错误")
+	}
+}
+
+// TestSetQuery 测试集合与 Query 的互操作
+func TestSetQuery(t *testing.T) {
+	s := NewSet(2, 4, 6)
+	q := From([]int{1, 2, 3, 4, 4, 5})
+
+	if got := FromSet(s).ToSlice(); !slices.Equal(got, []int{2, 4, 6}) {
+		t.Errorf("FromSet 错误: %v", got)
+	}
+	if got := ToSet(q).Values(); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("ToSet 错误: %v", got)
+	}
+	if got := q.Intersect(FromSet(s)).ToSlice(); !slices.Equal(got, []int{2, 4}) {
+		t.Errorf("Intersect(FromSet) 错误: %v", got)
+	}
+	if got := q.Except(FromSet(s)).ToSlice(); !slices.Equal(got, []int{1, 3, 5}) {
+		t.Errorf("Except(FromSet) 错误: %v", got)
+	}
+	if got := q.Union(FromSet(s)).ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Union(FromSet) 错误: %v", got)
+	}
+	if !Contains(FromSet(s), 6) || Contains(FromSet(s), 7) {
+		t.Errorf("Contains(FromSet) 错误")
+	}
+	if got := FromSet(s).Distinct().Count(); got != 3 {
+		t.Errorf("Distinct(FromSet) 期望 3，实际得到 %d", got)
+	}
+	// 结果与普通 Query 语义一致
+	plain := From(s.Values())
+	if !slices.Equal(q.Intersect(plain).ToSlice(), q.Intersect(FromSet(s)).ToSlice()) ||
+		!slices.Equal(q.Except(plain).ToSlice(), q.Except(FromSet(s)).ToSlice()) ||
+		!slices.Equal(q.Union(plain).ToSlice(), q.Union(FromSet(s)).ToSlice()) {
+		t.Errorf("FromSet 快速路径与普通路径结果不一致")
+	}
+	// 查询不会修改原集合
+	ToSet(FromSet(s)).Add(100)
+	if s.Has(100) {
+		t.Errorf("ToSet(FromSet) 应返回副本")
+	}
+}
@@ -12,30 +12,22 @@ func (q Query[T]) HasOrder() bool {
 
 // OrderBy 指定主要排序键，按升序对序列元素进行排序
 func OrderBy[T comparable, K cmp.Ordered](q Query[T], key func(T) K) Query[T] {
-	return orderBy(q, func(a, b T) int {
-		return cmp.Compare(key(a), key(b))
-	})
+	return orderByKey(q, key, false, true)
 }
 
 // OrderByDescending 指定主要排序键，按降序对序列元素进行排序
 func OrderByDescending[T comparable, K cmp.Ordered](q Query[T], key func(T) K) Query[T] {
-	return orderBy(q, func(a, b T) int {
-		return cmp.Compare(key(b), key(a)) // 降序关键：b 与 a 比较
-	})
+	return orderByKey(q, key, true, true)
 }
 
 // OrderByUnstable 指定主要排序键，按升序进行不稳定排序
 func OrderByUnstable[T comparable, K cmp.Ordered](q Query[T], key func(T) K) Query[T] {
-	return orderByUnstable(q, func(a, b T) int {
-		return cmp.Compare(key(a), key(b))
-	})
+	return orderByKey(q, key, false, false)
 }
 
 // OrderByDescendingUnstable 指定主要排序键，按降序进行不稳定排序
 func OrderByDescendingUnstable[T comparable, K cmp.Ordered](q Query[T], key func(T) K) Query[T] {
-	return orderByUnstable(q, func(a, b T) int {
-		return cmp.Compare(key(b), key(a))
-	})
+	return orderByKey(q, key, true, false)
 }
 
 // ThenBy 指定次要排序键，按升序对序列元素进行后续排序
@@ -123,31 +115,38 @@ func orderByWithMode[T comparable](q Query[T], cmpFn CompareFunc[T], stable bool
 		comparators = append(comparators, q.compare)
 	}
 	comparators = append(comparators, cmpFn)
-	combinedCmp := composeComparators(comparators)
-
-	sortStable := stable
+	sortStable, parallel := stable, false
 	if q.sortSource != nil {
-		sortStable = q.sortStable
+		sortStable, parallel = q.sortStable, q.sortParallel
 	}
+	return sortedQuery(source, comparators, sortStable, parallel, nil)
+}
 
+// sortedQuery 返回对 source 惰性排序的查询，每次遍历或收集时重新排序；keySort 非 nil 时为等价的基数排序
+func sortedQuery[T comparable](source Query[T], comparators []CompareFunc[T], stable, parallel bool, keySort func([]T)) Query[T] {
+	combinedCmp := composeComparators(comparators)
 	materialize := func() []T {
 		data := source.ToSlice()
+		if canceled(source.ctx) {
+			return nil
+		}
 		if combinedCmp == nil || len(data) <= 1 {
 			return data
 		}
-		if sortStable {
-			slices.SortStableFunc(data, combinedCmp)
+		if keySort != nil && len(data) >= radixSortMin {
+			keySort(data)
 		} else {
-			slices.SortFunc(data, combinedCmp)
+			sortData(data, combinedCmp, stable, parallel)
 		}
 		return data
 	}
 	return Query[T]{
+		ctx:     source.ctx,
 		compare: combinedCmp,
 		iterate: func(yield func(T) bool) {
 			data := materialize()
 			for _, item := range data {
-				if !yield(item) {
+				if canceled(source.ctx) || !yield(item) {
 					return
 				}
 			}
@@ -156,33 +155,46 @@ func orderByWithMode[T comparable](q Query[T], cmpFn CompareFunc[T], stable bool
 		materialize:  materialize,
 		sortSource:   &source,
 		sortCompares: comparators,
-		sortStable:   sortStable,
+		sortStable:   stable,
+		sortParallel: parallel,
 	}
 }
 
-// OrderedQuery 包含已有的排序规则，供特定场景复用
+// OrderedQuery 包含已有的排序规则，供特定场景复用。
+// 内嵌的 Query 本身即为惰性排序结果，未单独声明的方法以及 Select 等泛型函数（传入 oq.Query）均按排序结果执行
 type OrderedQuery[T comparable] struct {
 	Query[T]
 	sortCompares []CompareFunc[T]
 	sortStable   bool
+	sortParallel bool
+	sorted       []T // Materialize 缓存的已排序结果
 }
 
 // Order 指定排序规则
 func (q Query[T]) Order(comparator CompareFunc[T]) OrderedQuery[T] {
-	return OrderedQuery[T]{
-		Query:        q,
-		sortCompares: []CompareFunc[T]{comparator},
-		sortStable:   true,
-	}
+	return newOrderedQuery(q, []CompareFunc[T]{comparator}, true, false)
 }
 
 // OrderUnstable 指定排序规则并使用不稳定排序
 func (q Query[T]) OrderUnstable(comparator CompareFunc[T]) OrderedQuery[T] {
+	return newOrderedQuery(q, []CompareFunc[T]{comparator}, false, false)
+}
+
+func newOrderedQuery[T comparable](source Query[T], comparators []CompareFunc[T], stable, parallel bool) OrderedQuery[T] {
 	return OrderedQuery[T]{
-		Query:        q,
-		sortCompares: []CompareFunc[T]{comparator},
-		sortStable:   false,
+		Query:        sortedQuery(source, comparators, stable, parallel, nil),
+		sortCompares: comparators,
+		sortStable:   stable,
+		sortParallel: parallel,
+	}
+}
+
+// source 返回排序前的原始查询，Materialize 后为已排序的缓存
+func (oq OrderedQuery[T]) source() Query[T] {
+	if oq.Query.sortSource != nil {
+		return *oq.Query.sortSource
 	}
+	return oq.Query
 }
 
 // Asc 根据键选择器生成升序比较器
@@ -199,6 +211,11 @@ func Desc[T comparable, K cmp.Ordered](selector func(T) K) CompareFunc[T] {
 	}
 }
 
+// OrderLike 按与当前查询相同的排序规则对 q 排序，两者的 Union / Intersect / Except 可采用归并
+func (oq OrderedQuery[T]) OrderLike(q Query[T]) OrderedQuery[T] {
+	return newOrderedQuery(q, oq.sortCompares, oq.sortStable, oq.sortParallel)
+}
+
 // Then 添加后续排序规则
 func (oq OrderedQuery[T]) Then(comparator CompareFunc[T]) OrderedQuery[T] {
 	comparators := make([]CompareFunc[T], 0, len(oq.sortCompares)+1)
@@ -214,16 +231,18 @@ func (oq OrderedQuery[T]) Then(comparator CompareFunc[T]) OrderedQuery[T] {
 		stable = true
 	}
 
-	return OrderedQuery[T]{
-		Query:        oq.Query,
-		sortCompares: comparators,
-		sortStable:   stable,
-	}
+	return newOrderedQuery(oq.source(), comparators, stable, oq.sortParallel)
 }
 
-// ToQuery 将 OrderedQuery 转换为已排序的 Query
+// ToQuery 将 OrderedQuery 转换为已排序的 Query，保留排序规则供 ThenBy 及有序集合运算识别
 func (oq OrderedQuery[T]) ToQuery() Query[T] {
-	return From(oq.sortedSlice())
+	q := From(oq.view())
+	q.compare = composeComparators(oq.sortCompares)
+	q.sortCompares = oq.sortCompares
+	q.sortStable = oq.sortStable
+	q.sortParallel = oq.sortParallel
+	q.ctx = oq.ctx
+	return q
 }
 
 // ToSlice 提供已排序结果
@@ -232,17 +251,10 @@ func (oq OrderedQuery[T]) ToSlice() []T {
 }
 
 func (oq OrderedQuery[T]) sortedSlice() []T {
-	data := oq.Query.ToSlice()
-	cmpFn := composeComparators(oq.sortCompares)
-	if cmpFn == nil || len(data) <= 1 {
-		return data
-	}
-	if oq.sortStable {
-		slices.SortStableFunc(data, cmpFn)
-	} else {
-		slices.SortFunc(data, cmpFn)
+	if oq.sorted != nil {
+		return slices.Clone(oq.sorted)
 	}
-	return data
+	return oq.Query.ToSlice()
 }
 
 // First 返回已排序第一个元素
@@ -255,6 +267,16 @@ func (oq OrderedQuery[T]) Last() T {
 	return oq.ToQuery().Last()
 }
 
+// LastWith 代理
+func (oq OrderedQuery[T]) LastWith(predicate func(T) bool) T {
+	return oq.ToQuery().LastWith(predicate)
+}
+
+// LastWithOK 代理
+func (oq OrderedQuery[T]) LastWithOK(predicate func(T) bool) (T, bool) {
+	return oq.ToQuery().LastWithOK(predicate)
+}
+
 // Take 代理
 func (oq OrderedQuery[T]) Take(count int) Query[T] {
 	return oq.ToQuery().Take(count)
@@ -330,31 +352,46 @@ func (oq OrderedQuery[T]) ForEachIndexed(action func(int, T) bool) {
 	oq.ToQuery().ForEachIndexed(action)
 }
 
-// Distinct 代理 (仅当 T 可比较时有效)
+// Distinct 代理，利用已排序特性仅缓存比较结果相等的一段元素
 func (oq OrderedQuery[T]) Distinct() Query[T] {
-	return Query[T]{
-		iterate: func(yield func(T) bool) {
-			seen := make(map[T]struct{})
-			for item := range oq.ToQuery().iterate {
-				if _, ok := seen[item]; !ok {
-					seen[item] = struct{}{}
-					if !yield(item) {
-						return
-					}
-				}
-			}
-		},
+	cmpFn := composeComparators(oq.sortCompares)
+	if cmpFn == nil {
+		return Distinct(oq.ToQuery())
+	}
+	return DistinctSorted(oq.ToQuery(), cmpFn)
+}
+
+// Union 代理，q2 与当前查询使用同一排序规则时采用归并，结果保持有序
+func (oq OrderedQuery[T]) Union(q2 Query[T]) Query[T] {
+	if sameComparators(oq.sortCompares, q2.sortCompares) {
+		return UnionSorted(composeComparators(oq.sortCompares), oq.ToQuery(), q2)
+	}
+	return Union(oq.ToQuery(), q2)
+}
+
+// Intersect 代理，q2 与当前查询使用同一排序规则时采用归并，结果保持有序
+func (oq OrderedQuery[T]) Intersect(q2 Query[T]) Query[T] {
+	if sameComparators(oq.sortCompares, q2.sortCompares) {
+		return IntersectSorted(composeComparators(oq.sortCompares), oq.ToQuery(), q2)
+	}
+	return Intersect(oq.ToQuery(), q2)
+}
+
+// Except 代理，q2 与当前查询使用同一排序规则时采用归并，结果保持有序
+func (oq OrderedQuery[T]) Except(q2 Query[T]) Query[T] {
+	if sameComparators(oq.sortCompares, q2.sortCompares) {
+		return ExceptSorted(composeComparators(oq.sortCompares), oq.ToQuery(), q2)
 	}
+	return Except(oq.ToQuery(), q2)
 }
 
-// IndexOf 代理 (仅当 T 可比较时有效)
+// IndexOf 返回元素在排序结果中首次出现的位置，先二分定位比较结果相等的一段再逐个比较
 func (oq OrderedQuery[T]) IndexOf(value T) int {
-	index := 0
-	for item := range oq.ToQuery().iterate {
-		if item == value {
-			return index
+	data, cmpFn := oq.lookup()
+	for i := lowerBound(data, value, cmpFn); i < len(data) && cmpFn(data[i], value) == 0; i++ {
+		if data[i] == value {
+			return i
 		}
-		index++
 	}
 	return -1
 }
@@ -0,0 +1,179 @@
+package linq
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// 并行执行、结果顺序不确定的方法，不参与顺序一致性比较
+var orderedParitySkip = map[string]bool{
+	"ForEachParallel":     true,
+	"ForEachParallelCtx":  true,
+	"ForEachParallelOpts": true,
+	"ForEachParallelErr":  true,
+}
+
+// TestOrderedQueryParity 通过反射遍历 Query 的全部方法，确认 OrderedQuery 上的同名方法均按排序结果执行
+func TestOrderedQueryParity(t *testing.T) {
+	data := []int{5, 3, 8, 1, 9, 2, 7, 3}
+	asc := Asc(func(i int) int { return i })
+	sorted := slices.Clone(data)
+	slices.Sort(sorted)
+	reference := From(sorted)
+	reference.compare = asc
+
+	queryType := reflect.TypeFor[Query[int]]()
+	for i := 0; i < queryType.NumMethod(); i++ {
+		name := queryType.Method(i).Name
+		if orderedParitySkip[name] {
+			continue
+		}
+		oq := From(slices.Clone(data)).Order(asc)
+		method := reflect.ValueOf(oq).MethodByName(name)
+		if !method.IsValid() {
+			t.Errorf("OrderedQuery 缺少方法 %s", name)
+			continue
+		}
+		want := reflect.ValueOf(reference).MethodByName(name)
+
+		gotLog, wantLog := &[]any{}, &[]any{}
+		gotArgs, ok := parityArgs(method.Type(), gotLog)
+		if !ok {
+			t.Errorf("%s 的参数类型未被测试支持，请补充 parityArgs", name)
+			continue
+		}
+		wantArgs, _ := parityArgs(want.Type(), wantLog)
+
+		got := parityCall(method, gotArgs)
+		expected := parityCall(want, wantArgs)
+		if !reflect.DeepEqual(got, expected) || !reflect.DeepEqual(*gotLog, *wantLog) {
+			t.Errorf("%s 未按排序结果执行: 期望 %v %v，实际得到 %v %v", name, expected, *wantLog, got, *gotLog)
+		}
+	}
+}
+
+// parityArgs 按参数类型构造调用参数，回调函数的入参记录到 log
+func parityArgs(fn reflect.Type, log *[]any) ([]reflect.Value, bool) {
+	n := fn.NumIn()
+	if fn.IsVariadic() {
+		n-- // 可变参数留空
+	}
+	args := make([]reflect.Value, 0, n)
+	for i := 0; i < n; i++ {
+		in := fn.In(i)
+		switch {
+		case in.Kind() == reflect.Int:
+			args = append(args, reflect.ValueOf(2).Convert(in))
+		case in == reflect.TypeFor[context.Context]():
+			args = append(args, reflect.ValueOf(context.Background()))
+		case in == reflect.TypeFor[Query[int]]():
+			args = append(args, reflect.ValueOf(From([]int{4, 1, 9})))
+		case in == reflect.TypeFor[[]int]():
+			args = append(args, reflect.ValueOf([]int{}))
+		case in.Kind() == reflect.String:
+			args = append(args, reflect.ValueOf("parity"))
+		case in.Kind() == reflect.Func:
+			args = append(args, parityFunc(in, log))
+		default:
+			return nil, false
+		}
+	}
+	return args, true
+}
+
+// parityFunc 构造回调：比较函数返回 a-b，其余函数记录入参后按最后一个 int 参数返回结果
+func parityFunc(fn reflect.Type, log *[]any) reflect.Value {
+	return reflect.MakeFunc(fn, func(in []reflect.Value) []reflect.Value {
+		if fn.NumIn() == 2 && fn.NumOut() == 1 && fn.Out(0).Kind() == reflect.Int {
+			return []reflect.Value{reflect.ValueOf(int(in[0].Int() - in[1].Int()))}
+		}
+		params := make([]any, len(in))
+		for i, v := range in {
+			params[i] = v.Interface()
+		}
+		*log = append(*log, params)
+		x := in[len(in)-1].Int()
+		out := make([]reflect.Value, fn.NumOut())
+		for i := range out {
+			switch typ := fn.Out(i); {
+			case typ.Kind() == reflect.Bool:
+				out[i] = reflect.ValueOf(x%3 != 0)
+			case typ.Kind() == reflect.Map:
+				m := reflect.MakeMap(typ)
+				m.SetMapIndex(reflect.ValueOf("v"), reflect.ValueOf(int(x)))
+				out[i] = m
+			default:
+				out[i] = reflect.ValueOf(x).Convert(typ)
+			}
+		}
+		return out
+	})
+}
+
+// parityCall 调用方法并把结果统一转换为可比较的值，panic 亦作为结果
+func parityCall(method reflect.Value, args []reflect.Value) (result []any) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = append(result, fmt.Sprint("panic: ", r))
+		}
+	}()
+	for _, out := range method.Call(args) {
+		switch v := out.Interface().(type) {
+		case Query[int]:
+			result = append(result, v.ToSlice())
+		case OrderedQuery[int]:
+			result = append(result, v.ToSlice())
+		case iter.Seq[int]:
+			result = append(result, slices.Collect(v))
+		case <-chan int:
+			var items []int
+			for item := range v {
+				items = append(items, item)
+			}
+			result = append(result, items)
+		case func():
+			v()
+			result = append(result, "func()")
+		default:
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// TestOrderedQueryGenericFunctions 测试泛型函数作用于 OrderedQuery 时保持排序
+func TestOrderedQueryGenericFunctions(t *testing.T) {
+	oq := From([]int{5, 3, 8, 1, 6}).Order(Asc(func(i int) int { return i }))
+
+	if got := Select(oq.Query, func(i int) int { return i * 10 }).ToSlice(); !slices.Equal(got, []int{10, 30, 50, 60, 80}) {
+		t.Errorf("Select 未按排序结果执行: %v", got)
+	}
+	groups := GroupBy(oq.Query, func(i int) bool { return i%2 == 0 }).ToSlice()
+	// 分组输出顺序不固定，按键取奇数组
+	if len(groups) != 2 {
+		t.Fatalf("GroupBy 期望 2 个分组，实际得到 %v", groups)
+	}
+	odd := groups[0]
+	if odd.Key {
+		odd = groups[1]
+	}
+	if odd.Key || !slices.Equal(odd.Value, []int{1, 3, 5}) {
+		t.Errorf("GroupBy 未按排序结果执行: %v", groups)
+	}
+	if got := oq.Concat(From([]int{0})).ToSlice(); !slices.Equal(got, []int{1, 3, 5, 6, 8, 0}) {
+		t.Errorf("Concat 未按排序结果执行: %v", got)
+	}
+
+	// Then 基于原始数据重新排序，不受已排序结果影响
+	type pair struct{ A, B int }
+	pairs := From([]pair{{2, 1}, {1, 2}, {2, 0}, {1, 1}}).
+		Order(Asc(func(p pair) int { return p.A })).
+		Then(Desc(func(p pair) int { return p.B }))
+	if got := pairs.Seq(); !slices.Equal(slices.Collect(got), []pair{{1, 2}, {1, 1}, {2, 1}, {2, 0}}) {
+		t.Errorf("Then 后 Seq 错误: %v", slices.Collect(got))
+	}
+}
@@ -0,0 +1,290 @@
+package linq
+
+import (
+	"container/heap"
+	"iter"
+)
+
+// 有序输入快速路径：输入须已按 cmp 升序排列。排序由 cmp 决定，元素相等仍以 == 判断，
+// 仅需缓存 cmp 结果为 0 的一段元素（全序比较器下为 O(1) 内存）。
+
+// MergeSorted 惰性 k 路归并多个已排序序列，相等元素按序列参数顺序输出（稳定）
+func MergeSorted[T comparable](cmp CompareFunc[T], qs ...Query[T]) Query[T] {
+	capHint := 0
+	for _, q := range qs {
+		capHint += q.capacity
+	}
+	return Query[T]{
+		ctx: queriesContext(qs),
+		iterate: func(yield func(T) bool) {
+			h := &mergeHeap[T]{cmp: cmp, items: make([]mergeHead[T], 0, len(qs))}
+			for i, q := range qs {
+				next, stop := iter.Pull(q.Seq())
+				defer stop()
+				if item, ok := next(); ok {
+					h.items = append(h.items, mergeHead[T]{item: item, source: i, next: next})
+				}
+			}
+			heap.Init(h)
+			for h.Len() > 0 {
+				top := &h.items[0]
+				if !yield(top.item) {
+					return
+				}
+				if item, ok := top.next(); ok {
+					top.item = item
+					heap.Fix(h, 0)
+				} else {
+					heap.Pop(h)
+				}
+			}
+		},
+		capacity: capHint,
+	}
+}
+
+type mergeHead[T comparable] struct {
+	item   T
+	source int
+	next   func() (T, bool)
+}
+
+type mergeHeap[T comparable] struct {
+	cmp   CompareFunc[T]
+	items []mergeHead[T]
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.items) }
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	if r := h.cmp(h.items[i].item, h.items[j].item); r != 0 {
+		return r < 0
+	}
+	return h.items[i].source < h.items[j].source
+}
+func (h *mergeHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *mergeHeap[T]) Push(x any)    { h.items = append(h.items, x.(mergeHead[T])) }
+func (h *mergeHeap[T]) Pop() any {
+	n := len(h.items) - 1
+	item := h.items[n]
+	h.items = h.items[:n]
+	return item
+}
+
+// DistinctUntilChanged 过滤与前一个元素相同的连续重复元素
+func DistinctUntilChanged[T comparable](q Query[T]) Query[T] {
+	return Query[T]{
+		ctx: q.ctx,
+		iterate: func(yield func(T) bool) {
+			var prev T
+			first := true
+			for item := range q.Seq() {
+				if !first && item == prev {
+					continue
+				}
+				first = false
+				prev = item
+				if !yield(item) {
+					return
+				}
+			}
+		},
+		capacity: q.capacity,
+	}
+}
+
+// DistinctSorted 对已按 cmp 排序的序列去重，结果与 Distinct 相同但无需哈希全部元素
+func DistinctSorted[T comparable](q Query[T], cmp CompareFunc[T]) Query[T] {
+	return Query[T]{
+		ctx: q.ctx,
+		iterate: func(yield func(T) bool) {
+			var run runSet[T]
+			var head T
+			for item := range q.Seq() {
+				if run.len() > 0 && cmp(head, item) != 0 {
+					run.reset()
+				}
+				if run.has(item) {
+					continue
+				}
+				if run.len() == 0 {
+					head = item
+				}
+				run.add(item)
+				if !yield(item) {
+					return
+				}
+			}
+		},
+		capacity: q.capacity,
+	}
+}
+
+// UnionSorted 对两个已排序序列取并集，结果有序且去重
+func UnionSorted[T comparable](cmp CompareFunc[T], q1, q2 Query[T]) Query[T] {
+	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
+		iterate: func(yield func(T) bool) {
+			var out runSet[T]
+			mergeRuns(cmp, q1, q2, mergeUnion, func(run1, run2 []T) bool {
+				out.reset()
+				for _, run := range [2][]T{run1, run2} {
+					for _, item := range run {
+						if out.has(item) {
+							continue
+						}
+						out.add(item)
+						if !yield(item) {
+							return false
+						}
+					}
+				}
+				return true
+			})
+		},
+		capacity: q1.capacity + q2.capacity,
+	}
+}
+
+// IntersectSorted 对两个已排序序列取交集，结果有序且去重
+func IntersectSorted[T comparable](cmp CompareFunc[T], q1, q2 Query[T]) Query[T] {
+	return filterSortedRuns(cmp, q1, q2, mergeIntersect, true)
+}
+
+// ExceptSorted 对两个已排序序列取差集（q1 中有而 q2 中没有），结果有序且去重
+func ExceptSorted[T comparable](cmp CompareFunc[T], q1, q2 Query[T]) Query[T] {
+	return filterSortedRuns(cmp, q1, q2, mergeExcept, false)
+}
+
+// filterSortedRuns 输出 q1 中去重后且是否存在于 q2 同段内与 keep 一致的元素
+func filterSortedRuns[T comparable](cmp CompareFunc[T], q1, q2 Query[T], mode mergeMode, keep bool) Query[T] {
+	return Query[T]{
+		ctx: firstContext(q1.ctx, q2.ctx),
+		iterate: func(yield func(T) bool) {
+			var other, out runSet[T]
+			mergeRuns(cmp, q1, q2, mode, func(run1, run2 []T) bool {
+				other.reset()
+				for _, item := range run2 {
+					other.add(item)
+				}
+				out.reset()
+				for _, item := range run1 {
+					if other.has(item) != keep || out.has(item) {
+						continue
+					}
+					out.add(item)
+					if !yield(item) {
+						return false
+					}
+				}
+				return true
+			})
+		},
+		capacity: q1.capacity,
+	}
+}
+
+type mergeMode uint8
+
+const (
+	mergeUnion     mergeMode = iota // 任一序列未结束即继续
+	mergeIntersect                  // 任一序列结束即停止
+	mergeExcept                     // q1 结束即停止
+)
+
+// mergeRuns 同步推进两个已排序序列，每次取出 cmp 相等的一段（各自可能为空）交给 emit
+func mergeRuns[T comparable](cmp CompareFunc[T], q1, q2 Query[T], mode mergeMode, emit func(run1, run2 []T) bool) {
+	next1, stop1 := iter.Pull(q1.Seq())
+	defer stop1()
+	next2, stop2 := iter.Pull(q2.Seq())
+	defer stop2()
+
+	a, ok1 := next1()
+	b, ok2 := next2()
+	var run1, run2 []T
+	for {
+		switch mode {
+		case mergeUnion:
+			if !ok1 && !ok2 {
+				return
+			}
+		case mergeIntersect:
+			if !ok1 || !ok2 {
+				return
+			}
+		case mergeExcept:
+			if !ok1 {
+				return
+			}
+		}
+		var head T
+		switch {
+		case !ok2:
+			head = a
+		case !ok1:
+			head = b
+		case cmp(a, b) <= 0:
+			head = a
+		default:
+			head = b
+		}
+		run1, run2 = run1[:0], run2[:0]
+		for ok1 && cmp(a, head) == 0 {
+			run1 = append(run1, a)
+			a, ok1 = next1()
+		}
+		for ok2 && cmp(b, head) == 0 {
+			run2 = append(run2, b)
+			b, ok2 = next2()
+		}
+		if !emit(run1, run2) {
+			return
+		}
+	}
+}
+
+// runSet 缓存 cmp 相等的一段元素，段较短时线性查找，超过阈值后转为哈希表
+type runSet[T comparable] struct {
+	items []T
+	index map[T]struct{}
+}
+
+const runSetThreshold = 32
+
+func (r *runSet[T]) len() int { return len(r.items) }
+
+func (r *runSet[T]) reset() {
+	r.items = r.items[:0]
+	r.index = nil
+}
+
+func (r *runSet[T]) has(item T) bool {
+	if r.index != nil {
+		_, ok := r.index[item]
+		return ok
+	}
+	for _, v := range r.items {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *runSet[T]) add(item T) {
+	r.items = append(r.items, item)
+	if r.index != nil {
+		r.index[item] = struct{}{}
+		return
+	}
+	if len(r.items) > runSetThreshold {
+		r.index = make(map[T]struct{}, len(r.items)*2)
+		for _, v := range r.items {
+			r.index[v] = struct{}{}
+		}
+	}
+}
+
+// sameComparators 判断两组比较器是否为同一组（共享底层数组），由同一次 Order/Then 及 OrderLike 派生的查询共享
+func sameComparators[T comparable](a, b []CompareFunc[T]) bool {
+	return len(a) > 0 && len(a) == len(b) && &a[0] == &b[0]
+}
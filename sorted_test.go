@@ -0,0 +1,153 @@
+package linq
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// TestMergeSorted 测试 k 路归并
+func TestMergeSorted(t *testing.T) {
+	asc := Asc(func(i int) int { return i })
+	got := MergeSorted(asc, From([]int{1, 4, 7}), From([]int{2, 5, 8}), QueryEmpty[int](), From([]int{3, 6, 9, 10})).ToSlice()
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("归并结果错误: %v", got)
+	}
+	if got := MergeSorted(asc).Count(); got != 0 {
+		t.Errorf("无输入期望空序列，实际得到 %d", got)
+	}
+
+	// 相等元素按参数顺序稳定输出
+	type tagged struct{ Key, Src int }
+	byKey := Asc(func(v tagged) int { return v.Key })
+	merged := MergeSorted(byKey,
+		From([]tagged{{1, 0}, {2, 0}}),
+		From([]tagged{{1, 1}, {2, 1}}),
+	).ToSlice()
+	if !slices.Equal(merged, []tagged{{1, 0}, {1, 1}, {2, 0}, {2, 1}}) {
+		t.Errorf("归并应稳定: %v", merged)
+	}
+
+	// 无限序列配合 Take
+	evens := RangeFrom(0, 2)
+	odds := RangeFrom(1, 2)
+	if got := MergeSorted(asc, evens, odds).Take(5).ToSlice(); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("无限序列归并错误: %v", got)
+	}
+}
+
+// TestDistinctSorted 测试有序去重
+func TestDistinctSorted(t *testing.T) {
+	if got := DistinctUntilChanged(From([]int{1, 1, 2, 1, 1, 3, 3})).ToSlice(); !slices.Equal(got, []int{1, 2, 1, 3}) {
+		t.Errorf("DistinctUntilChanged 错误: %v", got)
+	}
+	asc := Asc(func(i int) int { return i })
+	if got := DistinctSorted(From([]int{1, 1, 2, 3, 3, 3, 4}), asc).ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("DistinctSorted 错误: %v", got)
+	}
+
+	// 比较器存在并列时仍以 == 判断相等
+	type person struct {
+		Name string
+		Age  int
+	}
+	people := []person{{"a", 20}, {"b", 20}, {"a", 20}, {"c", 30}}
+	byAge := Asc(func(p person) int { return p.Age })
+	if got := DistinctSorted(From(people), byAge).ToSlice(); !slices.Equal(got, []person{{"a", 20}, {"b", 20}, {"c", 30}}) {
+		t.Errorf("并列元素去重错误: %v", got)
+	}
+
+	// 超长并列段切换为哈希表
+	many := make([]int, 0, 200)
+	for i := 0; i < 100; i++ {
+		many = append(many, i, i)
+	}
+	constant := func(a, b int) int { return 0 }
+	if got := DistinctSorted(From(many), constant).Count(); got != 100 {
+		t.Errorf("超长并列段期望 100 个，实际得到 %d", got)
+	}
+}
+
+// TestSortedSetOperations 测试有序集合运算
+func TestSortedSetOperations(t *testing.T) {
+	asc := Asc(func(i int) int { return i })
+	a := From([]int{1, 2, 2, 3, 5, 8})
+	b := From([]int{2, 3, 3, 4, 8, 9})
+
+	if got := UnionSorted(asc, a, b).ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4, 5, 8, 9}) {
+		t.Errorf("UnionSorted 错误: %v", got)
+	}
+	if got := IntersectSorted(asc, a, b).ToSlice(); !slices.Equal(got, []int{2, 3, 8}) {
+		t.Errorf("IntersectSorted 错误: %v", got)
+	}
+	if got := ExceptSorted(asc, a, b).ToSlice(); !slices.Equal(got, []int{1, 5}) {
+		t.Errorf("ExceptSorted 错误: %v", got)
+	}
+	if got := UnionSorted(asc, a, QueryEmpty[int]()).ToSlice(); !slices.Equal(got, []int{1, 2, 3, 5, 8}) {
+		t.Errorf("UnionSorted 空序列错误: %v", got)
+	}
+	if got := IntersectSorted(asc, RangeFrom(0, 3), RangeFrom(0, 5)).Take(3).ToSlice(); !slices.Equal(got, []int{0, 15, 30}) {
+		t.Errorf("IntersectSorted 无限序列错误: %v", got)
+	}
+
+	// 随机数据与哈希实现结果集合一致
+	rng := rand.New(rand.NewPCG(1, 2))
+	x := make([]int, 300)
+	y := make([]int, 300)
+	for i := range x {
+		x[i], y[i] = rng.IntN(200), rng.IntN(200)
+	}
+	slices.Sort(x)
+	slices.Sort(y)
+	check := func(name string, got, want []int) {
+		t.Helper()
+		slices.Sort(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s 与哈希实现不一致", name)
+		}
+	}
+	check("Union", UnionSorted(asc, From(x), From(y)).ToSlice(), Union(From(x), From(y)).ToSlice())
+	check("Intersect", IntersectSorted(asc, From(x), From(y)).ToSlice(), Intersect(From(x), From(y)).ToSlice())
+	check("Except", ExceptSorted(asc, From(x), From(y)).ToSlice(), Except(From(x), From(y)).ToSlice())
+}
+
+// TestOrderedQuerySortedPaths 测试 OrderedQuery 自动选择有序快速路径
+func TestOrderedQuerySortedPaths(t *testing.T) {
+	asc := Asc(func(i int) int { return i })
+	a := From([]int{5, 1, 3, 1, 8}).Order(asc)
+	b := a.OrderLike(From([]int{3, 9, 1, 4}))
+
+	if !sameComparators(a.sortCompares, b.ToQuery().sortCompares) {
+		t.Fatalf("共享比较器应被识别")
+	}
+	if got := a.Union(b.ToQuery()).ToSlice(); !slices.Equal(got, []int{1, 3, 4, 5, 8, 9}) {
+		t.Errorf("Union 期望有序结果，实际得到 %v", got)
+	}
+	if got := a.Intersect(b.ToQuery()).ToSlice(); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("Intersect 错误: %v", got)
+	}
+	if got := a.Except(b.ToQuery()).ToSlice(); !slices.Equal(got, []int{5, 8}) {
+		t.Errorf("Except 错误: %v", got)
+	}
+	if got := a.Distinct().ToSlice(); !slices.Equal(got, []int{1, 3, 5, 8}) {
+		t.Errorf("Distinct 错误: %v", got)
+	}
+
+	// 各自排序的查询即使比较器相同也回退为哈希实现
+	if sameComparators(a.sortCompares, From([]int{9, 3}).Order(asc).sortCompares) {
+		t.Errorf("各自调用 Order 不应视为同一组比较器")
+	}
+	other := From([]int{9, 3}).Order(Asc(func(i int) int { return i }))
+	if sameComparators(a.sortCompares, other.sortCompares) {
+		t.Errorf("不同闭包不应视为同一比较器")
+	}
+	if sameComparators(a.sortCompares, a.Then(asc).sortCompares) {
+		t.Errorf("Then 后的比较器组不应与原组相同")
+	}
+	if got := a.Union(other.ToQuery()).ToSlice(); !slices.Equal(got, []int{1, 3, 5, 8, 9}) {
+		t.Errorf("回退 Union 错误: %v", got)
+	}
+	if got := a.Except(From([]int{1, 8})).ToSlice(); !slices.Equal(got, []int{3, 5}) {
+		t.Errorf("普通 Query 参数 Except 错误: %v", got)
+	}
+}
@@ -0,0 +1,452 @@
+package linq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// 推送式事件流：Stream 由数据源主动推送元素，适合 WebSocket 消息、指标等事件。
+// Stream 是冷的，每次订阅（Subscribe、Collect、ToChannel）都会重新运行数据源；
+// 时间相关操作均通过 WithClock 指定的 Clock 完成，测试中可替换为可控时钟。
+
+// ErrStreamTimeout Timeout 在限定时间内未收到元素
+var ErrStreamTimeout = errors.New("linq: stream timeout")
+
+// Stream 推送式事件流
+type Stream[T any] struct {
+	// run 向 emit 依次推送元素直到数据源结束（返回 nil）、出错或 ctx 取消（返回 ctx.Err()）；
+	// emit 返回 false 表示下游不再需要元素，此时应返回 nil
+	run   func(ctx context.Context, emit func(T) bool) error
+	clock Clock
+}
+
+// NewStream 由 run 创建事件流：run 依次调用 emit 推送元素，emit 返回 false 时应停止并返回 nil，
+// ctx 取消时应返回 ctx.Err()；emit 不可并发调用
+func NewStream[T any](run func(ctx context.Context, emit func(T) bool) error) Stream[T] {
+	return Stream[T]{run: run}
+}
+
+// StreamOf 依次推送给定元素
+func StreamOf[T any](items ...T) Stream[T] {
+	return NewStream(func(ctx context.Context, emit func(T) bool) error {
+		for _, item := range items {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if !emit(item) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// StreamFromChannel 推送通道中的元素，通道关闭时结束
+func StreamFromChannel[T any](ch <-chan T) Stream[T] {
+	return NewStream(func(ctx context.Context, emit func(T) bool) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case item, ok := <-ch:
+				if !ok || !emit(item) {
+					return nil
+				}
+			}
+		}
+	})
+}
+
+// StreamFromQuery 推送查询的元素
+func StreamFromQuery[T comparable](q Query[T]) Stream[T] {
+	return NewStream(func(ctx context.Context, emit func(T) bool) error {
+		for item := range q.Seq() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if !emit(item) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// StreamToQuery 将事件流转换为 Query，每次遍历时订阅一次，遍历提前结束时取消订阅
+func StreamToQuery[T comparable](ctx context.Context, s Stream[T]) Query[T] {
+	return Query[T]{
+		ctx: ctx,
+		iterate: func(yield func(T) bool) {
+			subCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			for item := range s.ToChannel(subCtx) {
+				if !yield(item) {
+					return
+				}
+			}
+		},
+	}
+}
+
+// WithClock 指定后续时间操作使用的时钟，默认 SystemClock()
+func (s Stream[T]) WithClock(clock Clock) Stream[T] {
+	s.clock = clock
+	return s
+}
+
+func (s Stream[T]) clockOrSystem() Clock {
+	if s.clock == nil {
+		return systemClock{}
+	}
+	return s.clock
+}
+
+// derive 以 run 创建下游事件流，沿用时钟
+func (s Stream[T]) derive(run func(ctx context.Context, emit func(T) bool) error) Stream[T] {
+	return Stream[T]{run: run, clock: s.clock}
+}
+
+// Subscribe 订阅并对每个元素执行 onNext，阻塞直到事件流结束；返回数据源或操作的错误，ctx 取消时返回 ctx.Err()
+func (s Stream[T]) Subscribe(ctx context.Context, onNext func(T)) error {
+	return s.run(ctx, func(item T) bool {
+		onNext(item)
+		return true
+	})
+}
+
+// Collect 订阅并收集全部元素，出错时返回已收集的元素与错误
+func (s Stream[T]) Collect(ctx context.Context) ([]T, error) {
+	var result []T
+	err := s.Subscribe(ctx, func(item T) { result = append(result, item) })
+	return result, err
+}
+
+// ToChannel 订阅并将元素发送到通道，事件流结束、出错或 ctx 取消时关闭通道；需要错误时使用 Subscribe
+func (s Stream[T]) ToChannel(ctx context.Context) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		s.run(ctx, func(item T) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case ch <- item:
+				return true
+			}
+		})
+	}()
+	return ch
+}
+
+// Where 只推送满足条件的元素
+func (s Stream[T]) Where(predicate func(T) bool) Stream[T] {
+	return s.derive(func(ctx context.Context, emit func(T) bool) error {
+		return s.run(ctx, func(item T) bool {
+			return !predicate(item) || emit(item)
+		})
+	})
+}
+
+// Take 推送前 count 个元素后结束
+func (s Stream[T]) Take(count int) Stream[T] {
+	return s.derive(func(ctx context.Context, emit func(T) bool) error {
+		if count <= 0 {
+			return nil
+		}
+		n := 0
+		return s.run(ctx, func(item T) bool {
+			n++
+			return emit(item) && n < count
+		})
+	})
+}
+
+// StreamSelect 转换每个元素
+func StreamSelect[T, V any](s Stream[T], selector func(T) V) Stream[V] {
+	return Stream[V]{
+		clock: s.clock,
+		run: func(ctx context.Context, emit func(V) bool) error {
+			return s.run(ctx, func(item T) bool {
+				return emit(selector(item))
+			})
+		},
+	}
+}
+
+// Merge 同时订阅多个事件流并按到达顺序推送，全部结束后结束；任一出错时取消其余并返回该错误
+func (s Stream[T]) Merge(others ...Stream[T]) Stream[T] {
+	streams := append([]Stream[T]{s}, others...)
+	result := s
+	for _, other := range others {
+		if result.clock == nil {
+			result.clock = other.clock
+		}
+	}
+	result.run = func(ctx context.Context, emit func(T) bool) error {
+		mergeCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var (
+			mu       sync.Mutex
+			stopped  bool
+			firstErr error
+			wg       sync.WaitGroup
+		)
+		for _, stream := range streams {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := stream.run(mergeCtx, func(item T) bool {
+					mu.Lock()
+					defer mu.Unlock()
+					if stopped {
+						return false
+					}
+					if !emit(item) {
+						stopped = true
+						cancel()
+					}
+					return !stopped
+				})
+				if err != nil && mergeCtx.Err() == nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}()
+		}
+		wg.Wait()
+		if firstErr != nil {
+			return firstErr
+		}
+		if stopped {
+			return nil
+		}
+		return ctx.Err()
+	}
+	return result
+}
+
+// errStreamStopped 下游不再需要元素时取消上游的原因
+var errStreamStopped = errors.New("linq: stream stopped")
+
+// timedEmitter 供时间操作使用：上游元素与定时器回调在 mu 保护下串行处理，下游的 emit 不会被并发调用
+type timedEmitter[V any] struct {
+	mu     sync.Mutex
+	clock  Clock
+	emit   func(V) bool
+	cancel context.CancelCauseFunc
+	done   bool        // 下游不再需要元素、已超时或上游已结束，之后不再推送
+	gen    int         // 定时器代数，用于忽略已停止但仍在执行的回调
+	stop   func() bool // 当前定时器
+}
+
+// runTimed 在可取消的上下文中运行上游，onItem 在持有 mu 时处理每个元素，onEnd 在上游正常结束后处理剩余元素
+func runTimed[T, V any](ctx context.Context, s Stream[T], emit func(V) bool,
+	setup func(e *timedEmitter[V]), onItem func(e *timedEmitter[V], item T), onEnd func(e *timedEmitter[V])) error {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	e := &timedEmitter[V]{clock: s.clockOrSystem(), emit: emit, cancel: cancel}
+	e.mu.Lock()
+	if setup != nil {
+		setup(e)
+	}
+	e.mu.Unlock()
+	err := s.run(runCtx, func(item T) bool {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if !e.done {
+			onItem(e, item)
+		}
+		return !e.done
+	})
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.disarm()
+	switch cause := context.Cause(runCtx); {
+	case errors.Is(cause, errStreamStopped):
+		return nil
+	case errors.Is(cause, ErrStreamTimeout):
+		return ErrStreamTimeout
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case err == nil && !e.done && onEnd != nil:
+		onEnd(e)
+	}
+	e.done = true
+	return err
+}
+
+// send 推送 v，下游不再需要元素时取消上游；须持有 mu
+func (e *timedEmitter[V]) send(v V) bool {
+	if e.done {
+		return false
+	}
+	if !e.emit(v) {
+		e.done = true
+		e.cancel(errStreamStopped)
+	}
+	return !e.done
+}
+
+// fail 以 cause 结束并取消上游；须持有 mu
+func (e *timedEmitter[V]) fail(cause error) {
+	e.done = true
+	e.cancel(cause)
+}
+
+// arm 重新计时，d 后在持有 mu 时执行 f；须持有 mu
+func (e *timedEmitter[V]) arm(d time.Duration, f func()) {
+	e.disarm()
+	gen := e.gen
+	e.stop = e.clock.AfterFunc(d, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if !e.done && gen == e.gen {
+			f()
+		}
+	})
+}
+
+// disarm 停止当前定时器；须持有 mu
+func (e *timedEmitter[V]) disarm() {
+	e.gen++
+	if e.stop != nil {
+		e.stop()
+		e.stop = nil
+	}
+}
+
+// Debounce 元素到达后 d 内没有新元素时才推送该元素；数据源结束时推送尚未推送的最后一个元素
+func (s Stream[T]) Debounce(d time.Duration) Stream[T] {
+	return s.derive(func(ctx context.Context, emit func(T) bool) error {
+		var (
+			latest  T
+			pending bool
+		)
+		return runTimed(ctx, s, emit, nil, func(e *timedEmitter[T], item T) {
+			latest, pending = item, true
+			e.arm(d, func() {
+				pending = false
+				e.send(latest)
+			})
+		}, func(e *timedEmitter[T]) {
+			if pending {
+				e.send(latest)
+			}
+		})
+	})
+}
+
+// Throttle 推送元素后 d 内到达的元素均被丢弃
+func (s Stream[T]) Throttle(d time.Duration) Stream[T] {
+	return s.derive(func(ctx context.Context, emit func(T) bool) error {
+		clock := s.clockOrSystem()
+		var last time.Time
+		emitted := false
+		return s.run(ctx, func(item T) bool {
+			now := clock.Now()
+			if emitted && now.Sub(last) < d {
+				return true
+			}
+			last, emitted = now, true
+			return emit(item)
+		})
+	})
+}
+
+// Sample 每隔 d 推送这段时间内最新到达的元素，期间没有新元素时不推送
+func (s Stream[T]) Sample(d time.Duration) Stream[T] {
+	return s.derive(func(ctx context.Context, emit func(T) bool) error {
+		var (
+			latest T
+			fresh  bool
+		)
+		var tick func(e *timedEmitter[T])
+		tick = func(e *timedEmitter[T]) {
+			e.arm(d, func() {
+				tick(e)
+				if fresh {
+					fresh = false
+					e.send(latest)
+				}
+			})
+		}
+		return runTimed(ctx, s, emit, tick, func(_ *timedEmitter[T], item T) {
+			latest, fresh = item, true
+		}, nil)
+	})
+}
+
+// Timeout 订阅后或上一个元素之后 d 内没有新元素时以 ErrStreamTimeout 结束
+func (s Stream[T]) Timeout(d time.Duration) Stream[T] {
+	return s.derive(func(ctx context.Context, emit func(T) bool) error {
+		expire := func(e *timedEmitter[T]) {
+			e.arm(d, func() { e.fail(ErrStreamTimeout) })
+		}
+		return runTimed(ctx, s, emit, expire, func(e *timedEmitter[T], item T) {
+			e.disarm()
+			if e.send(item) {
+				expire(e)
+			}
+		}, nil)
+	})
+}
+
+// BufferCount 每 count 个元素作为一批推送，数据源结束时推送剩余元素
+func BufferCount[T any](s Stream[T], count int) Stream[[]T] {
+	count = max(count, 1)
+	return Stream[[]T]{
+		clock: s.clock,
+		run: func(ctx context.Context, emit func([]T) bool) error {
+			var buf []T
+			stopped := false
+			err := s.run(ctx, func(item T) bool {
+				buf = append(buf, item)
+				if len(buf) < count {
+					return true
+				}
+				batch := buf
+				buf = nil
+				stopped = !emit(batch)
+				return !stopped
+			})
+			if err == nil && !stopped && len(buf) > 0 {
+				emit(buf)
+			}
+			return err
+		},
+	}
+}
+
+// BufferTime 每隔 d 将这段时间内到达的元素作为一批推送（没有元素时不推送），数据源结束时推送剩余元素
+func BufferTime[T any](s Stream[T], d time.Duration) Stream[[]T] {
+	return Stream[[]T]{
+		clock: s.clock,
+		run: func(ctx context.Context, emit func([]T) bool) error {
+			var buf []T
+			flush := func(e *timedEmitter[[]T]) {
+				if len(buf) > 0 {
+					batch := buf
+					buf = nil
+					e.send(batch)
+				}
+			}
+			var tick func(e *timedEmitter[[]T])
+			tick = func(e *timedEmitter[[]T]) {
+				e.arm(d, func() {
+					tick(e)
+					flush(e)
+				})
+			}
+			return runTimed(ctx, s, emit, tick, func(_ *timedEmitter[[]T], item T) {
+				buf = append(buf, item)
+			}, flush)
+		},
+	}
+}
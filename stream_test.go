@@ -0,0 +1,182 @@
+package linq
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestStreamBasics 测试基本操作及与 Query、通道的互通
+func TestStreamBasics(t *testing.T) {
+	ctx := context.Background()
+	got, err := StreamSelect(StreamOf(1, 2, 3, 4, 5, 6).Where(func(i int) bool { return i%2 == 0 }).Take(2),
+		func(i int) string { return string(rune('a' + i)) }).Collect(ctx)
+	if err != nil || !slices.Equal(got, []string{"c", "e"}) {
+		t.Errorf("期望 [c e]，实际得到 %v 与 %v", got, err)
+	}
+
+	if got, _ := StreamFromQuery(QueryRange(1, 3)).Collect(ctx); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("StreamFromQuery 期望 [1 2 3]，实际得到 %v", got)
+	}
+	q := StreamToQuery(ctx, StreamFromQuery(QueryRange(1, 100)))
+	if got := q.Where(func(i int) bool { return i%10 == 0 }).Take(3).ToSlice(); !slices.Equal(got, []int{10, 20, 30}) {
+		t.Errorf("StreamToQuery 期望 [10 20 30]，实际得到 %v", got)
+	}
+	if got := q.Count(); got != 100 {
+		t.Errorf("期望每次遍历重新订阅得到 100 个元素，实际 %d 个", got)
+	}
+
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := range 3 {
+			ch <- i
+		}
+	}()
+	var out []int
+	for item := range StreamFromChannel(ch).ToChannel(ctx) {
+		out = append(out, item)
+	}
+	if !slices.Equal(out, []int{0, 1, 2}) {
+		t.Errorf("通道互通期望 [0 1 2]，实际得到 %v", out)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := StreamFromChannel(make(chan int)).Collect(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("期望 context.Canceled，实际得到 %v", err)
+	}
+}
+
+// TestStreamMerge 合并多个事件流，出错时取消其余事件流
+func TestStreamMerge(t *testing.T) {
+	ctx := context.Background()
+	got, err := StreamOf(1, 2, 3).Merge(StreamOf(4, 5), StreamOf(6)).Collect(ctx)
+	slices.Sort(got)
+	if err != nil || !slices.Equal(got, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("期望合并全部 6 个元素，实际得到 %v 与 %v", got, err)
+	}
+	if got, err := StreamOf(1, 2, 3).Merge(StreamOf(4, 5, 6)).Take(4).Collect(ctx); err != nil || len(got) != 4 {
+		t.Errorf("Take 期望 4 个元素且无错误，实际得到 %v 与 %v", got, err)
+	}
+
+	errBoom := errors.New("boom")
+	failing := NewStream(func(context.Context, func(int) bool) error { return errBoom })
+	blocked := StreamFromChannel(make(chan int))
+	if _, err := blocked.Merge(failing).Collect(ctx); !errors.Is(err, errBoom) {
+		t.Errorf("期望返回 boom 并取消阻塞的事件流，实际得到 %v", err)
+	}
+}
+
+// scriptStep 脚本的一步：推送 item、等待累计注册 wait 个定时器或推进 advance 时间
+type scriptStep struct {
+	item    int
+	wait    int
+	advance time.Duration
+}
+
+// scripted 按脚本推送元素并推进时间，元素与定时器回调在同一 goroutine 中依次发生，结果确定
+func scripted(clock *fakeClock, steps ...scriptStep) Stream[int] {
+	return NewStream(func(ctx context.Context, emit func(int) bool) error {
+		for _, step := range steps {
+			switch {
+			case step.wait > 0:
+				clock.waitTimers(step.wait)
+			case step.advance > 0:
+				clock.Advance(step.advance)
+			default:
+				if !emit(step.item) {
+					return nil
+				}
+			}
+		}
+		return nil
+	}).WithClock(clock)
+}
+
+func emitStep(item int) scriptStep           { return scriptStep{item: item} }
+func waitStep(n int) scriptStep              { return scriptStep{wait: n} }
+func advanceStep(d time.Duration) scriptStep { return scriptStep{advance: d} }
+
+// TestStreamTimeOperators 使用可控时钟测试时间操作
+func TestStreamTimeOperators(t *testing.T) {
+	ctx := context.Background()
+	ms := time.Millisecond
+
+	clock := newFakeClock()
+	got, err := scripted(clock,
+		emitStep(1), waitStep(1), advanceStep(50*ms),
+		emitStep(2), waitStep(2), advanceStep(100*ms),
+		emitStep(3), waitStep(3),
+	).Debounce(100 * ms).Collect(ctx)
+	if err != nil || !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("Debounce 期望 [2 3]，实际得到 %v 与 %v", got, err)
+	}
+
+	// 定时器回调中下游不再需要元素时取消上游
+	clock = newFakeClock()
+	got, err = scripted(clock,
+		emitStep(1), waitStep(1), advanceStep(100*ms), emitStep(2), emitStep(3),
+	).Debounce(100 * ms).Take(1).Collect(ctx)
+	if err != nil || !slices.Equal(got, []int{1}) {
+		t.Errorf("Debounce 后 Take(1) 期望 [1]，实际得到 %v 与 %v", got, err)
+	}
+
+	clock = newFakeClock()
+	got, _ = scripted(clock,
+		emitStep(1), advanceStep(50*ms), emitStep(2), advanceStep(50*ms), emitStep(3), emitStep(4),
+	).Throttle(100 * ms).Collect(ctx)
+	if !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("Throttle 期望 [1 3]，实际得到 %v", got)
+	}
+
+	clock = newFakeClock()
+	got, _ = scripted(clock,
+		emitStep(1), emitStep(2), waitStep(1), advanceStep(100*ms),
+		emitStep(3), emitStep(4), waitStep(2), advanceStep(100*ms),
+		emitStep(5), waitStep(3), advanceStep(100*ms), emitStep(6), waitStep(4), advanceStep(100*ms),
+	).Sample(100 * ms).Collect(ctx)
+	if !slices.Equal(got, []int{2, 4, 5, 6}) {
+		t.Errorf("Sample 期望 [2 4 5 6]，实际得到 %v", got)
+	}
+
+	clock = newFakeClock()
+	batches, _ := BufferTime(scripted(clock,
+		emitStep(1), emitStep(2), waitStep(1), advanceStep(100*ms),
+		emitStep(3), waitStep(2), advanceStep(100*ms), waitStep(3), advanceStep(100*ms),
+		emitStep(4),
+	), 100*ms).Collect(ctx)
+	if len(batches) != 3 || !slices.Equal(batches[0], []int{1, 2}) || !slices.Equal(batches[1], []int{3}) || !slices.Equal(batches[2], []int{4}) {
+		t.Errorf("BufferTime 期望 [[1 2] [3] [4]]，实际得到 %v", batches)
+	}
+
+	clock = newFakeClock()
+	got, err = scripted(clock,
+		emitStep(1), waitStep(2), advanceStep(50*ms), emitStep(2), waitStep(3), advanceStep(100*ms), emitStep(3),
+	).Timeout(100 * ms).Collect(ctx)
+	if !errors.Is(err, ErrStreamTimeout) || !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Timeout 期望 [1 2] 与 ErrStreamTimeout，实际得到 %v 与 %v", got, err)
+	}
+}
+
+// TestStreamBufferCount 按个数分批，剩余元素在结束时推送
+func TestStreamBufferCount(t *testing.T) {
+	batches, err := BufferCount(StreamOf(1, 2, 3, 4, 5), 2).Collect(context.Background())
+	if err != nil || len(batches) != 3 || !slices.Equal(batches[2], []int{5}) {
+		t.Errorf("期望 [[1 2] [3 4] [5]]，实际得到 %v 与 %v", batches, err)
+	}
+	first, _ := BufferCount(StreamOf(1, 2, 3, 4, 5), 2).Take(1).Collect(context.Background())
+	if len(first) != 1 || !slices.Equal(first[0], []int{1, 2}) {
+		t.Errorf("Take(1) 期望 [[1 2]]，实际得到 %v", first)
+	}
+}
+
+// TestStreamSystemClock 系统时钟下结束时推送防抖中的元素
+func TestStreamSystemClock(t *testing.T) {
+	got, err := StreamOf(1, 2, 3).Debounce(time.Hour).Timeout(time.Hour).Collect(context.Background())
+	if err != nil || !slices.Equal(got, []int{3}) {
+		t.Errorf("期望 [3]，实际得到 %v 与 %v", got, err)
+	}
+}
@@ -0,0 +1,220 @@
+package linq
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// 执行追踪：在管道中插入 Trace(name) 观测经过该点的元素，事件发送给 Trace 指定的观察者及全局观察者。
+// 未插入 Trace 的管道不受影响；创建 Trace 点时没有任何观察者则不插入，之后移除全局观察者的 Trace 点直接透传上游。
+// 相邻两个 Trace 点之间即为一个阶段：阶段输入数为上一个 Trace 点的 Elements，输出数为本点的 Elements。
+
+// TraceKind 追踪事件类型
+type TraceKind uint8
+
+const (
+	TraceStage       TraceKind = iota // 一次遍历经过 Trace 点结束
+	TraceMaterialize                  // 排序上游一次性物化并排序
+	TracePanic                        // 并发 worker 或追踪点上游发生 panic
+)
+
+func (k TraceKind) String() string {
+	switch k {
+	case TraceStage:
+		return "stage"
+	case TraceMaterialize:
+		return "materialize"
+	case TracePanic:
+		return "panic"
+	}
+	return "unknown"
+}
+
+// TraceEvent 追踪事件
+type TraceEvent struct {
+	Kind     TraceKind
+	Stage    string        // Trace 名称，worker 报告的 TracePanic 为空
+	Elements int           // 经过 Trace 点（或物化得到）的元素个数
+	Duration time.Duration // 上游耗时，不含下游处理元素的时间
+	Stopped  bool          // 下游提前终止了遍历（如 Take、First、Any）
+	Worker   int           // TracePanic：worker 序号，追踪点报告时为 -1
+	Panic    any           // TracePanic：panic 的值
+}
+
+// Observer 追踪事件的接收者，可能被并发调用
+type Observer interface {
+	Observe(TraceEvent)
+}
+
+// ObserverFunc 函数形式的 Observer
+type ObserverFunc func(TraceEvent)
+
+func (f ObserverFunc) Observe(e TraceEvent) { f(e) }
+
+type observerBox struct{ Observer }
+
+var globalObserver atomic.Pointer[observerBox]
+
+// SetObserver 设置全局观察者，nil 表示移除；全局观察者接收所有 Trace 点的事件及并发 worker 的 panic
+func SetObserver(o Observer) {
+	if o == nil {
+		globalObserver.Store(nil)
+		return
+	}
+	globalObserver.Store(&observerBox{o})
+}
+
+// multiObserver 依次转发给多个观察者
+type multiObserver []Observer
+
+func (m multiObserver) Observe(e TraceEvent) {
+	for _, o := range m {
+		o.Observe(e)
+	}
+}
+
+// observerFor 合并 Trace 指定的观察者与当前的全局观察者，均没有时返回 nil
+func observerFor(local []Observer) Observer {
+	global := globalObserver.Load()
+	switch {
+	case global == nil && len(local) == 0:
+		return nil
+	case global == nil && len(local) == 1:
+		return local[0]
+	case global == nil:
+		return multiObserver(local)
+	case len(local) == 0:
+		return global.Observer
+	}
+	return append(multiObserver{global.Observer}, local...)
+}
+
+// tracePanic 向全局观察者及 local（Trace 指定的观察者，可为 nil）报告并发 worker 的 panic
+func tracePanic(local Observer, worker int, r any) {
+	e := TraceEvent{Kind: TracePanic, Worker: worker, Panic: r}
+	if global := globalObserver.Load(); global != nil {
+		global.Observe(e)
+	}
+	if local != nil {
+		local.Observe(e)
+	}
+}
+
+// Trace 插入追踪点，每次遍历结束时发送 TraceStage 事件；observers 为仅作用于该点的观察者，与全局观察者同时生效。
+// 上游为排序结果时先发送 TraceMaterialize 事件（经 ToSlice 收集时只发送该事件），并保留排序信息，
+// 其后的 ThenBy 等直接在排序源上重新排序；上游有一次性收集的快速路径（如 Select、Distinct）时经 ToSlice 收集仍走该路径。
+// observers 还会收到上游 panic 经过该点时的 TracePanic 事件（Worker 为 -1），以及直接在该点之后执行的并发操作中 worker 的 panic。
+// 调用时既无 observers 也未设置全局观察者则原样返回 q，不引入任何开销
+func (q Query[T]) Trace(name string, observers ...Observer) Query[T] {
+	var local []Observer
+	for _, o := range observers {
+		if o != nil {
+			local = append(local, o)
+		}
+	}
+	if len(local) == 0 && globalObserver.Load() == nil {
+		return q
+	}
+	var own Observer
+	switch len(local) {
+	case 0:
+	case 1:
+		own = local[0]
+	default:
+		own = multiObserver(local)
+	}
+	// 排序上游的物化记为 TraceMaterialize，其他上游经 ToSlice 快速收集时记为一次完整的 TraceStage
+	var materialize func(obs Observer) []T
+	if q.materialize != nil {
+		kind := TraceStage
+		if q.sortSource != nil {
+			kind = TraceMaterialize
+		}
+		materialize = func(obs Observer) []T {
+			if obs == nil {
+				return q.materialize()
+			}
+			start := time.Now()
+			data := q.materialize()
+			obs.Observe(TraceEvent{Kind: kind, Stage: name, Elements: len(data), Duration: time.Since(start)})
+			return data
+		}
+	}
+	result := Query[T]{
+		ctx:      q.ctx,
+		compare:  q.compare,
+		capacity: q.capacity,
+		observer: own,
+		iterate: func(yield func(T) bool) {
+			obs := observerFor(local)
+			// 上游的 panic 报告给该点的观察者后继续抛出，下游（yield 内）的 panic 不经过该点
+			inYield := false
+			if own != nil {
+				defer func() {
+					if inYield {
+						return
+					}
+					if r := recover(); r != nil {
+						own.Observe(TraceEvent{Kind: TracePanic, Stage: name, Worker: -1, Panic: r})
+						panic(r)
+					}
+				}()
+			}
+			start := time.Now()
+			seq := q.Seq()
+			if q.sortSource != nil && materialize != nil {
+				seq = slices.Values(materialize(obs))
+			}
+			if obs == nil {
+				seq(yield)
+				return
+			}
+			count, stopped := 0, false
+			var downstream time.Duration
+			for item := range seq {
+				count++
+				t := time.Now()
+				inYield = true
+				ok := yield(item)
+				inYield = false
+				downstream += time.Since(t)
+				if !ok {
+					stopped = true
+					break
+				}
+			}
+			obs.Observe(TraceEvent{Kind: TraceStage, Stage: name, Elements: count, Duration: time.Since(start) - downstream, Stopped: stopped})
+		},
+		sortSource:   q.sortSource,
+		sortCompares: q.sortCompares,
+		sortStable:   q.sortStable,
+		sortParallel: q.sortParallel,
+	}
+	if materialize != nil {
+		result.materialize = func() []T { return materialize(observerFor(local)) }
+	}
+	return result
+}
+
+// SlogObserver 将追踪事件写入 slog 日志：阶段与物化事件使用 level，panic 使用 Error；logger 为 nil 时使用 slog.Default()
+func SlogObserver(logger *slog.Logger, level slog.Level) Observer {
+	return ObserverFunc(func(e TraceEvent) {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+		if e.Kind == TracePanic {
+			l.LogAttrs(context.Background(), slog.LevelError, "linq panic",
+				slog.Int("worker", e.Worker), slog.Any("panic", e.Panic))
+			return
+		}
+		l.LogAttrs(context.Background(), level, "linq "+e.Kind.String(),
+			slog.String("stage", e.Stage),
+			slog.Int("elements", e.Elements),
+			slog.Duration("duration", e.Duration),
+			slog.Bool("stopped", e.Stopped))
+	})
+}
@@ -0,0 +1,282 @@
+package linq
+
+import (
+	"bytes"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recorder 记录收到的事件
+type recorder struct {
+	mu     sync.Mutex
+	events []TraceEvent
+}
+
+func (r *recorder) Observe(e TraceEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) stages() map[string]TraceEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := make(map[string]TraceEvent)
+	for _, e := range r.events {
+		m[e.Stage] = e
+	}
+	return m
+}
+
+// TestTraceStages 测试各追踪点的元素个数与结果不变
+func TestTraceStages(t *testing.T) {
+	rec := &recorder{}
+	q := QueryRange(1, 100).Trace("source", rec).
+		Where(func(i int) bool { return i%3 == 0 }).Trace("where", rec)
+	got := Select(q, func(i int) int { return i * 2 }).Trace("select", rec).ToSlice()
+	if len(got) != 33 || got[0] != 6 {
+		t.Fatalf("期望 33 个元素且首个为 6，实际得到 %d 个 %v", len(got), got[:1])
+	}
+
+	stages := rec.stages()
+	for name, want := range map[string]int{"source": 100, "where": 33, "select": 33} {
+		e, ok := stages[name]
+		if !ok {
+			t.Fatalf("缺少阶段 %s 的事件", name)
+		}
+		if e.Kind != TraceStage || e.Elements != want || e.Stopped {
+			t.Errorf("阶段 %s 期望 %d 个元素且未提前终止，实际得到 %+v", name, want, e)
+		}
+		if e.Duration < 0 {
+			t.Errorf("阶段 %s 耗时不应为负: %v", name, e.Duration)
+		}
+	}
+	// 上游先结束，事件按上游到下游的顺序发送
+	var order []string
+	for _, e := range rec.events {
+		order = append(order, e.Stage)
+	}
+	if !slices.Equal(order, []string{"source", "where", "select"}) {
+		t.Errorf("期望事件顺序 source、where、select，实际得到 %v", order)
+	}
+}
+
+// TestTraceStopped 测试下游提前终止
+func TestTraceStopped(t *testing.T) {
+	rec := &recorder{}
+	q := From([]int{1, 2, 3, 4, 5}).Trace("src", rec)
+	if got := q.Take(2).ToSlice(); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("期望 [1 2]，实际得到 %v", got)
+	}
+	if !q.Any() {
+		t.Fatal("期望 Any 为 true")
+	}
+	if len(rec.events) != 2 {
+		t.Fatalf("期望 2 个事件，实际得到 %d", len(rec.events))
+	}
+	for i, want := range []int{2, 1} {
+		if e := rec.events[i]; !e.Stopped || e.Elements != want {
+			t.Errorf("事件 %d 期望提前终止且 %d 个元素，实际得到 %+v", i, want, e)
+		}
+	}
+}
+
+// TestTraceMaterialize 测试排序上游的物化事件
+func TestTraceMaterialize(t *testing.T) {
+	rec := &recorder{}
+	q := OrderBy(From([]int{3, 1, 2}), func(i int) int { return i }).Trace("sorted", rec)
+	if got := q.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("期望 [1 2 3]，实际得到 %v", got)
+	}
+	if len(rec.events) != 1 || rec.events[0].Kind != TraceMaterialize || rec.events[0].Elements != 3 {
+		t.Errorf("收集时期望 1 个含 3 个元素的物化事件，实际得到 %+v", rec.events)
+	}
+
+	rec.events = nil
+	if first := q.First(); first != 1 {
+		t.Fatalf("期望 1，实际得到 %d", first)
+	}
+	var kinds []TraceKind
+	for _, e := range rec.events {
+		kinds = append(kinds, e.Kind)
+	}
+	if !slices.Equal(kinds, []TraceKind{TraceMaterialize, TraceStage}) || !rec.events[1].Stopped {
+		t.Errorf("遍历时期望物化事件后接提前终止的阶段事件，实际得到 %+v", rec.events)
+	}
+	if !q.HasOrder() {
+		t.Error("Trace 应保留排序信息")
+	}
+}
+
+// TestTraceGlobalObserver 测试全局观察者与 Trace 指定的观察者同时生效
+func TestTraceGlobalObserver(t *testing.T) {
+	global, local := &recorder{}, &recorder{}
+	SetObserver(global)
+	defer SetObserver(nil)
+
+	From([]int{1, 2, 3}).Trace("a").Trace("b", local).Count()
+	if len(global.events) != 2 || len(local.events) != 1 {
+		t.Errorf("期望全局 2 个、局部 1 个事件，实际得到 %d 和 %d", len(global.events), len(local.events))
+	}
+
+	SetObserver(nil)
+	From([]int{1, 2, 3}).Trace("a").Count()
+	if len(global.events) != 2 {
+		t.Errorf("移除后不应再收到事件，实际得到 %d 个", len(global.events))
+	}
+}
+
+// TestTracePanic 测试并发 worker 的 panic 报告给全局观察者
+func TestTracePanic(t *testing.T) {
+	rec := &recorder{}
+	SetObserver(rec)
+	defer SetObserver(nil)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("期望 panic boom，实际得到 %v", r)
+			}
+		}()
+		QueryRange(1, 10).ForEachParallel(func(i int) {
+			if i == 5 {
+				panic("boom")
+			}
+		}, 2)
+	}()
+	events := rec.stages()
+	e, ok := events[""]
+	if !ok || e.Kind != TracePanic || e.Panic != "boom" || e.Worker < 0 || e.Worker > 1 {
+		t.Errorf("期望 worker 0 或 1 的 panic 事件，实际得到 %+v", rec.events)
+	}
+}
+
+// TestTracePanicLocal Trace 指定的观察者收到其后并发操作及上游的 panic
+func TestTracePanicLocal(t *testing.T) {
+	mustPanic := func(f func()) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("期望 panic boom，实际得到 %v", r)
+			}
+		}()
+		f()
+	}
+	boom := func(i int) int {
+		if i == 5 {
+			panic("boom")
+		}
+		return i
+	}
+
+	rec := &recorder{}
+	mustPanic(func() { QueryRange(1, 10).Trace("src", rec).ForEachParallel(func(i int) { boom(i) }, 2) })
+	if len(rec.events) != 2 {
+		t.Fatalf("期望阶段与 panic 共 2 个事件，实际得到 %+v", rec.events)
+	}
+	if e := rec.stages()[""]; e.Kind != TracePanic || e.Panic != "boom" || e.Worker < 0 || e.Worker > 1 {
+		t.Errorf("期望 worker 0 或 1 的 panic 事件，实际得到 %+v", rec.events)
+	}
+
+	rec = &recorder{}
+	mustPanic(func() { SelectAsyncCtx(nil, QueryRange(1, 10), boom, 2).Trace("async", rec).Count() })
+	if e := rec.stages()["async"]; len(rec.events) != 1 || e.Kind != TracePanic || e.Panic != "boom" || e.Worker != -1 {
+		t.Errorf("期望追踪点报告上游的 panic，实际得到 %+v", rec.events)
+	}
+
+	// 下游的 panic 不属于追踪点上游
+	rec = &recorder{}
+	mustPanic(func() {
+		for i := range QueryRange(1, 10).Trace("src", rec).Seq() {
+			boom(i)
+		}
+	})
+	if len(rec.events) != 0 {
+		t.Errorf("下游的 panic 不应报告，实际得到 %+v", rec.events)
+	}
+}
+
+// TestTraceKeepsOrder 追踪排序结果后仍可在排序源上追加排序条件
+func TestTraceKeepsOrder(t *testing.T) {
+	rec := &recorder{}
+	data := []int{21, 12, 11, 22, 13}
+	sorted := OrderBy(From(data), func(i int) int { return i / 10 }).Trace("sorted", rec).Trace("again", rec)
+	if sorted.sortSource == nil || len(sorted.sortCompares) != 1 {
+		t.Fatal("期望 Trace 保留排序源与排序条件")
+	}
+	got := OrderByDescending(sorted, func(i int) int { return i % 10 }).ToSlice()
+	if want := []int{13, 12, 11, 22, 21}; !slices.Equal(got, want) {
+		t.Errorf("期望 %v，实际得到 %v", want, got)
+	}
+
+	rec.events = nil
+	sorted.ToSlice()
+	if len(rec.events) != 2 || rec.events[0].Kind != TraceMaterialize || rec.events[1].Kind != TraceMaterialize {
+		t.Errorf("期望两个追踪点各发送一次物化事件，实际得到 %+v", rec.events)
+	}
+}
+
+// TestTraceNoObserver 没有观察者时 Trace 原样返回查询，保留切片快速路径
+func TestTraceNoObserver(t *testing.T) {
+	q := From([]int{1, 2, 3}).Where(func(i int) bool { return i > 1 })
+	traced := q.Trace("x")
+	if traced.fastSlice == nil || traced.Count() != 2 {
+		t.Errorf("期望保留快速路径且结果为 2 个元素，实际得到 %d 个", traced.Count())
+	}
+
+	// 创建后移除全局观察者，遍历时透传且不发送事件
+	rec := &recorder{}
+	SetObserver(rec)
+	traced = q.Trace("x")
+	SetObserver(nil)
+	if got := traced.ToSlice(); !slices.Equal(got, []int{2, 3}) || len(rec.events) != 0 {
+		t.Errorf("期望 [2 3] 且无事件，实际得到 %v 和 %d 个事件", got, len(rec.events))
+	}
+}
+
+// TestSlogObserver 测试 slog 适配器输出
+func TestSlogObserver(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	obs := SlogObserver(logger, slog.LevelDebug)
+
+	From([]int{1, 2, 3}).Trace("numbers", obs).Count()
+	obs.Observe(TraceEvent{Kind: TracePanic, Worker: 3, Panic: "boom"})
+
+	out := buf.String()
+	for _, want := range []string{"level=DEBUG", `msg="linq stage"`, "stage=numbers", "elements=3", "stopped=false",
+		"level=ERROR", `msg="linq panic"`, "worker=3", "panic=boom"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("日志中缺少 %q:\n%s", want, out)
+		}
+	}
+}
+
+func BenchmarkTrace(b *testing.B) {
+	data := make([]int, 10000)
+	for i := range data {
+		data[i] = i
+	}
+	pipeline := func(q Query[int]) int {
+		return q.Where(func(i int) bool { return i%2 == 0 }).Count()
+	}
+	b.Run("Plain", func(b *testing.B) {
+		for b.Loop() {
+			pipeline(From(data).Where(func(int) bool { return true }))
+		}
+	})
+	b.Run("NoObserver", func(b *testing.B) {
+		for b.Loop() {
+			pipeline(From(data).Where(func(int) bool { return true }).Trace("x"))
+		}
+	})
+	b.Run("Observed", func(b *testing.B) {
+		obs := ObserverFunc(func(TraceEvent) {})
+		for b.Loop() {
+			pipeline(From(data).Where(func(int) bool { return true }).Trace("x", obs))
+		}
+	})
+}
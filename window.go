@@ -0,0 +1,238 @@
+package linq
+
+// WindowQuery 窗口查询，在已排序序列上按分区计算排名、偏移与累计聚合
+type WindowQuery[T comparable] struct {
+	source    OrderedQuery[T]
+	partition []CompareFunc[T]
+}
+
+// Window 将整个有序序列视为单一分区
+func (oq OrderedQuery[T]) Window() WindowQuery[T] {
+	return WindowQuery[T]{source: oq}
+}
+
+// PartitionBy 按比较器划分分区，比较结果为 0 的元素属于同一分区，分区内沿用已有排序规则
+func (oq OrderedQuery[T]) PartitionBy(comparators ...CompareFunc[T]) WindowQuery[T] {
+	return WindowQuery[T]{source: oq, partition: comparators}
+}
+
+// partitions 先按分区再按排序规则排序，返回各分区对应的子切片
+func (w WindowQuery[T]) partitions() [][]T {
+	if len(w.partition) == 0 {
+		if data := w.source.sortedSlice(); len(data) > 0 {
+			return [][]T{data}
+		}
+		return nil
+	}
+	data := w.source.source().ToSlice()
+	if len(data) == 0 {
+		return nil
+	}
+	comparators := make([]CompareFunc[T], 0, len(w.partition)+len(w.source.sortCompares))
+	comparators = append(comparators, w.partition...)
+	comparators = append(comparators, w.source.sortCompares...)
+	if cmpFn := composeComparators(comparators); cmpFn != nil {
+		sortData(data, cmpFn, w.source.sortStable, w.source.sortParallel)
+	}
+	partCmp := composeComparators(w.partition)
+	if partCmp == nil {
+		return [][]T{data}
+	}
+	var parts [][]T
+	start := 0
+	for i := 1; i < len(data); i++ {
+		if partCmp(data[i-1], data[i]) != 0 {
+			parts = append(parts, data[start:i])
+			start = i
+		}
+	}
+	return append(parts, data[start:])
+}
+
+// windowEach 依次对每个分区计算并输出 KV{元素, 值}，newFn 在每次遍历时创建计算函数以隔离状态
+func windowEach[T, V comparable](w WindowQuery[T], newFn func() func(part []T, i int) V) Query[KV[T, V]] {
+	return Query[KV[T, V]]{
+		ctx: w.source.ctx,
+		iterate: func(yield func(KV[T, V]) bool) {
+			yield = yieldUntil(w.source.ctx, yield)
+			fn := newFn()
+			for _, part := range w.partitions() {
+				for i, item := range part {
+					if !yield(KV[T, V]{Key: item, Value: fn(part, i)}) {
+						return
+					}
+				}
+			}
+		},
+		capacity: w.source.capacity,
+	}
+}
+
+// RowNumber 分区内从 1 开始的行号
+func RowNumber[T comparable](w WindowQuery[T]) Query[KV[T, int]] {
+	return windowEach(w, func() func([]T, int) int {
+		return func(_ []T, i int) int { return i + 1 }
+	})
+}
+
+// Rank 分区内排名，并列元素排名相同且后续排名跳跃（1, 1, 3）
+func Rank[T comparable](w WindowQuery[T]) Query[KV[T, int]] {
+	ties := composeComparators(w.source.sortCompares)
+	return windowEach(w, func() func([]T, int) int {
+		rank := 0
+		return func(part []T, i int) int {
+			if i == 0 || ties == nil || ties(part[i-1], part[i]) != 0 {
+				rank = i + 1
+			}
+			return rank
+		}
+	})
+}
+
+// DenseRank 分区内密集排名，并列元素排名相同且后续排名连续（1, 1, 2）
+func DenseRank[T comparable](w WindowQuery[T]) Query[KV[T, int]] {
+	ties := composeComparators(w.source.sortCompares)
+	return windowEach(w, func() func([]T, int) int {
+		rank := 0
+		return func(part []T, i int) int {
+			if i == 0 {
+				rank = 1
+			} else if ties == nil || ties(part[i-1], part[i]) != 0 {
+				rank++
+			}
+			return rank
+		}
+	})
+}
+
+// NTile 将分区尽量均分为 n 个桶并返回从 1 开始的桶号，靠前的桶多分配余数，n <= 0 时返回空
+func NTile[T comparable](w WindowQuery[T], n int) Query[KV[T, int]] {
+	if n <= 0 {
+		return QueryEmpty[KV[T, int]]()
+	}
+	return windowEach(w, func() func([]T, int) int {
+		return func(part []T, i int) int {
+			size, extra := len(part)/n, len(part)%n
+			if i < extra*(size+1) {
+				return i/(size+1) + 1
+			}
+			return extra + (i-extra*(size+1))/size + 1
+		}
+	})
+}
+
+// Lag 返回分区内向前第 offset 行的元素，不存在时返回 defaultValue
+func Lag[T comparable](w WindowQuery[T], offset int, defaultValue T) Query[KV[T, T]] {
+	return windowEach(w, func() func([]T, int) T {
+		return func(part []T, i int) T {
+			if j := i - offset; j >= 0 && j < len(part) {
+				return part[j]
+			}
+			return defaultValue
+		}
+	})
+}
+
+// Lead 返回分区内向后第 offset 行的元素，不存在时返回 defaultValue
+func Lead[T comparable](w WindowQuery[T], offset int, defaultValue T) Query[KV[T, T]] {
+	return Lag(w, -offset, defaultValue)
+}
+
+// RunningAggregate 分区内累计聚合，每个分区从 seed 重新开始
+func RunningAggregate[T, A comparable](w WindowQuery[T], seed A, fn func(A, T) A) Query[KV[T, A]] {
+	return windowEach(w, func() func([]T, int) A {
+		var acc A
+		return func(part []T, i int) A {
+			if i == 0 {
+				acc = seed
+			}
+			acc = fn(acc, part[i])
+			return acc
+		}
+	})
+}
+
+// RunningSum 分区内累计求和
+func RunningSum[T comparable, V Integer | Float](w WindowQuery[T], selector func(T) V) Query[KV[T, V]] {
+	return RunningAggregate(w, 0, func(acc V, item T) V { return acc + selector(item) })
+}
+
+// RunningAvg 分区内累计平均值
+func RunningAvg[T comparable, V Integer | Float](w WindowQuery[T], selector func(T) V) Query[KV[T, float64]] {
+	return windowEach(w, func() func([]T, int) float64 {
+		var sum float64
+		return func(part []T, i int) float64 {
+			if i == 0 {
+				sum = 0
+			}
+			sum += float64(selector(part[i]))
+			return sum / float64(i+1)
+		}
+	})
+}
+
+// RunningMin 分区内累计最小值
+func RunningMin[T comparable, V Integer | Float](w WindowQuery[T], selector func(T) V) Query[KV[T, V]] {
+	return windowEach(w, func() func([]T, int) V {
+		var min V
+		return func(part []T, i int) V {
+			if v := selector(part[i]); i == 0 || v < min {
+				min = v
+			}
+			return min
+		}
+	})
+}
+
+// RunningMax 分区内累计最大值
+func RunningMax[T comparable, V Integer | Float](w WindowQuery[T], selector func(T) V) Query[KV[T, V]] {
+	return windowEach(w, func() func([]T, int) V {
+		var max V
+		return func(part []T, i int) V {
+			if v := selector(part[i]); i == 0 || v > max {
+				max = v
+			}
+			return max
+		}
+	})
+}
+
+// MovingSum 分区内最近 size 行（含当前行）的滑动求和，size <= 0 时返回空
+func MovingSum[T comparable, V Integer | Float](w WindowQuery[T], selector func(T) V, size int) Query[KV[T, V]] {
+	if size <= 0 {
+		return QueryEmpty[KV[T, V]]()
+	}
+	return windowEach(w, func() func([]T, int) V {
+		var sum V
+		return func(part []T, i int) V {
+			if i == 0 {
+				sum = 0
+			}
+			sum += selector(part[i])
+			if i >= size {
+				sum -= selector(part[i-size])
+			}
+			return sum
+		}
+	})
+}
+
+// MovingAvg 分区内最近 size 行（含当前行）的滑动平均值，size <= 0 时返回空
+func MovingAvg[T comparable, V Integer | Float](w WindowQuery[T], selector func(T) V, size int) Query[KV[T, float64]] {
+	if size <= 0 {
+		return QueryEmpty[KV[T, float64]]()
+	}
+	return windowEach(w, func() func([]T, int) float64 {
+		var sum float64
+		return func(part []T, i int) float64 {
+			if i == 0 {
+				sum = 0
+			}
+			sum += float64(selector(part[i]))
+			if i >= size {
+				sum -= float64(selector(part[i-size]))
+			}
+			return sum / float64(min(i+1, size))
+		}
+	})
+}
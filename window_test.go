@@ -0,0 +1,125 @@
+package linq
+
+import (
+	"slices"
+	"testing"
+)
+
+type score struct {
+	Name  string
+	Dept  string
+	Score int
+}
+
+var scores = []score{
+	{"a", "x", 90},
+	{"b", "y", 80},
+	{"c", "x", 95},
+	{"d", "x", 90},
+	{"e", "y", 80},
+	{"f", "y", 70},
+	{"g", "x", 85},
+}
+
+// 提取 KV 结果中的值
+func windowValues[T, V comparable](q Query[KV[T, V]]) []V {
+	return Select(q, func(kv KV[T, V]) V { return kv.Value }).ToSlice()
+}
+
+// TestWindowRank 测试排名窗口函数
+func TestWindowRank(t *testing.T) {
+	oq := From(scores).Order(Desc(func(s score) int { return s.Score }))
+
+	rows := RowNumber(oq.Window()).ToSlice()
+	if rows[0].Key.Name != "c" || rows[6].Value != 7 {
+		t.Errorf("RowNumber 结果错误: %v", rows)
+	}
+	if got := windowValues(Rank(oq.Window())); !slices.Equal(got, []int{1, 2, 2, 4, 5, 5, 7}) {
+		t.Errorf("Rank 期望 [1 2 2 4 5 5 7]，实际得到 %v", got)
+	}
+	if got := windowValues(DenseRank(oq.Window())); !slices.Equal(got, []int{1, 2, 2, 3, 4, 4, 5}) {
+		t.Errorf("DenseRank 期望 [1 2 2 3 4 4 5]，实际得到 %v", got)
+	}
+	if got := windowValues(NTile(oq.Window(), 3)); !slices.Equal(got, []int{1, 1, 1, 2, 2, 3, 3}) {
+		t.Errorf("NTile 期望 [1 1 1 2 2 3 3]，实际得到 %v", got)
+	}
+	if got := windowValues(NTile(oq.Window(), 10)); !slices.Equal(got, []int{1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("NTile 桶数大于行数期望 [1..7]，实际得到 %v", got)
+	}
+	if got := NTile(oq.Window(), 0).Count(); got != 0 {
+		t.Errorf("NTile(0) 期望空序列，实际得到 %d", got)
+	}
+}
+
+// TestWindowPartition 测试分区窗口函数
+func TestWindowPartition(t *testing.T) {
+	w := From(scores).
+		Order(Desc(func(s score) int { return s.Score })).
+		PartitionBy(Asc(func(s score) string { return s.Dept }))
+
+	ranked := Rank(w).ToSlice()
+	names := Select(From(ranked), func(kv KV[score, int]) string { return kv.Key.Name }).ToSlice()
+	if !slices.Equal(names, []string{"c", "a", "d", "g", "b", "e", "f"}) {
+		t.Errorf("分区排序错误: %v", names)
+	}
+	if got := windowValues(From(ranked)); !slices.Equal(got, []int{1, 2, 2, 4, 1, 1, 3}) {
+		t.Errorf("分区 Rank 期望 [1 2 2 4 1 1 3]，实际得到 %v", got)
+	}
+	if got := windowValues(RowNumber(w)); !slices.Equal(got, []int{1, 2, 3, 4, 1, 2, 3}) {
+		t.Errorf("分区 RowNumber 错误: %v", got)
+	}
+	// 重复遍历结果一致
+	if got := windowValues(DenseRank(w)); !slices.Equal(got, windowValues(DenseRank(w))) {
+		t.Errorf("重复遍历结果不一致")
+	}
+}
+
+// TestWindowLagLead 测试偏移窗口函数
+func TestWindowLagLead(t *testing.T) {
+	w := From([]int{5, 1, 4, 2, 3}).Order(Asc(func(i int) int { return i })).Window()
+	if got := windowValues(Lag(w, 1, -1)); !slices.Equal(got, []int{-1, 1, 2, 3, 4}) {
+		t.Errorf("Lag 期望 [-1 1 2 3 4]，实际得到 %v", got)
+	}
+	if got := windowValues(Lead(w, 2, 0)); !slices.Equal(got, []int{3, 4, 5, 0, 0}) {
+		t.Errorf("Lead 期望 [3 4 5 0 0]，实际得到 %v", got)
+	}
+	// 环比差值
+	deltas := Select(Lag(w, 1, 0), func(kv KV[int, int]) int { return kv.Key - kv.Value }).ToSlice()
+	if !slices.Equal(deltas, []int{1, 1, 1, 1, 1}) {
+		t.Errorf("差值计算错误: %v", deltas)
+	}
+}
+
+// TestWindowAggregates 测试累计与滑动聚合
+func TestWindowAggregates(t *testing.T) {
+	w := From(scores).
+		Order(Asc(func(s score) string { return s.Name })).
+		PartitionBy(Asc(func(s score) string { return s.Dept }))
+	value := func(s score) int { return s.Score }
+
+	if got := windowValues(RunningSum(w, value)); !slices.Equal(got, []int{90, 185, 275, 360, 80, 160, 230}) {
+		t.Errorf("RunningSum 错误: %v", got)
+	}
+	if got := windowValues(RunningMin(w, value)); !slices.Equal(got, []int{90, 90, 90, 85, 80, 80, 70}) {
+		t.Errorf("RunningMin 错误: %v", got)
+	}
+	if got := windowValues(RunningMax(w, value)); !slices.Equal(got, []int{90, 95, 95, 95, 80, 80, 80}) {
+		t.Errorf("RunningMax 错误: %v", got)
+	}
+	if got := windowValues(RunningAvg(w, value)); !slices.Equal(got, []float64{90, 92.5, 275.0 / 3, 90, 80, 80, 230.0 / 3}) {
+		t.Errorf("RunningAvg 错误: %v", got)
+	}
+	if got := windowValues(MovingSum(w, value, 2)); !slices.Equal(got, []int{90, 185, 185, 175, 80, 160, 150}) {
+		t.Errorf("MovingSum 错误: %v", got)
+	}
+	if got := windowValues(MovingAvg(w, value, 2)); !slices.Equal(got, []float64{90, 92.5, 92.5, 87.5, 80, 80, 75}) {
+		t.Errorf("MovingAvg 错误: %v", got)
+	}
+	if got := MovingSum(w, value, 0).Count(); got != 0 {
+		t.Errorf("窗口大小为 0 期望空序列，实际得到 %d", got)
+	}
+	concat := RunningAggregate(w, "", func(acc string, s score) string { return acc + s.Name })
+	if got := windowValues(concat); !slices.Equal(got, []string{"a", "ac", "acd", "acdg", "b", "be", "bef"}) {
+		t.Errorf("RunningAggregate 错误: %v", got)
+	}
+}